@@ -2,20 +2,37 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
+	"database/sql"
 	"dataxo-backend-game-ms/internal/adapters/mapstore"
+	"dataxo-backend-game-ms/internal/adapters/prommetrics"
+	"dataxo-backend-game-ms/internal/adapters/sqlstore"
 	"dataxo-backend-game-ms/internal/domain"
 	"dataxo-backend-game-ms/internal/ports/restapi"
+	"dataxo-backend-game-ms/internal/ports/restapi/apispec"
+	"dataxo-backend-game-ms/internal/ports/restapi/authrest"
 	"dataxo-backend-game-ms/internal/ports/restapi/gamesrest"
+	"dataxo-backend-game-ms/internal/ports/restapi/playersrest"
+	"dataxo-backend-game-ms/internal/ports/restapi/usersrest"
 	"dataxo-backend-game-ms/internal/usecases/gameuc"
 	"dataxo-backend-game-ms/internal/usecases/gameuc/modes"
+	"dataxo-backend-game-ms/internal/usecases/ratinguc"
+	"dataxo-backend-game-ms/internal/usecases/useruc"
+	"dataxo-backend-game-ms/pkg/hmactoken"
+	"encoding/base64"
 	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/lmittmann/tint"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"log/slog"
+	_ "modernc.org/sqlite"
 	"net/http"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 )
@@ -27,7 +44,32 @@ var (
 	restReadTimeout  = 10 * time.Second
 	restWriteTimeout = 10 * time.Second
 
+	// address of the Prometheus metrics listener like :9090, the metrics aren't served if empty.
+	// It's separate from the REST API, so the metrics aren't exposed to the clients.
+	metricsAddrEnv = "METRICS_ADDR"
+	metricsPath    = "/metrics"
+
 	shutdownTimeout = 15 * time.Second
+	// the hint to the WebSocket and SSE clients closed by the shutdown
+	reconnectAfter = 5 * time.Second
+
+	wsMaxMessageSize int64 = 4 << 10
+	wsWriteWait            = 10 * time.Second
+	wsPongWait             = 30 * time.Second
+	wsPingPeriod           = wsPongWait * 9 / 10
+
+	// base64 encoded key of at least 32 bytes, random if empty
+	authTokenKeyEnv = "AUTH_TOKEN_KEY"
+	authTokenTTL    = 30 * 24 * time.Hour
+	// tokens are refreshed for this time after the login, then the user logs in again
+	authTokenRefreshLimit = 90 * 24 * time.Hour
+
+	// comma separated origins of the browser clients like https://example.com, * allows any origin.
+	// The origin of the server itself is always allowed.
+	corsAllowedOriginsEnv = "CORS_ALLOWED_ORIGINS"
+
+	// SQLite data source name of the users and ratings database, they are kept in memory if empty
+	usersDBEnv = "USERS_DB"
 )
 
 func main() {
@@ -42,6 +84,7 @@ func main() {
 	defer cancel()
 
 	gameRepo := mapstore.NewGameRepo()
+	seriesRepo := mapstore.NewSeriesRepo()
 
 	disappearingModeCfg := domain.DisappearingModeConfig{
 		PlayerFiguresLimit: 6,
@@ -56,17 +99,89 @@ func main() {
 		return
 	}
 
-	gameUC := gameuc.New(gameRepo, disappearingMode, log)
+	userRepo, ratingRepo, closeUserRepos, err := newUserRepos(ctx)
+	if err != nil {
+		log.Error("can't create user repos", slog.Any("error", err))
+		return
+	}
+	defer func() {
+		if err := closeUserRepos(); err != nil {
+			log.Error("can't close user repos", slog.Any("error", err))
+		}
+	}()
+	userUC := useruc.New(userRepo, log)
+	ratingUC := ratinguc.New(ratingRepo, userRepo, log)
+
+	metricsRegistry := prometheus.NewRegistry()
+	metricsRegistry.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	metrics := prommetrics.New(metricsRegistry)
 
-	jsonResponder := restapi.NewJsonResponder(log)
+	gameUC := gameuc.New(gameRepo, seriesRepo, disappearingMode, log,
+		gameuc.WithRater(ratingUC), gameuc.WithMetrics(metrics))
+	metricsRegistry.MustRegister(prommetrics.NewGamesCollector(gameUC))
+
+	errConverter := restapi.NewErrConverter(gamesrest.Errors...)
+	jsonResponder := restapi.NewJsonResponder(log, errConverter)
 
 	router := chi.NewRouter()
 	router.Use(middleware.RequestID)
-	router.Use(middleware.RequestLogger(restapi.NewLogFormatter(log, slog.LevelInfo)))
+	router.Use(middleware.RequestLogger(restapi.NewLogFormatter(log, slog.LevelInfo,
+		restapi.WithRequestObserver(metrics))))
 	router.Use(middleware.Recoverer)
 
-	gamesRestHandler := gamesrest.New(log, gameUC, jsonResponder, jsonResponder)
+	allowedOrigins := os.Getenv(corsAllowedOriginsEnv)
+	if strings.TrimSpace(allowedOrigins) == "" {
+		log.Warn("allowed origins are not set, browsers of other origins can't use the API and WebSocket",
+			slog.String("env", corsAllowedOriginsEnv))
+	}
+	originPolicy := restapi.NewOriginPolicy(strings.Split(allowedOrigins, ",")...)
+	// errors of the next middlewares must be readable by the allowed origins
+	router.Use(restapi.CORS(originPolicy, jsonResponder))
+
+	tokenKey, err := authTokenKey()
+	if err != nil {
+		log.Error("can't get auth token key", slog.Any("error", err))
+		return
+	}
+	if tokenKey == nil {
+		log.Warn("auth token key is not set, issued tokens will be invalid after restart",
+			slog.String("env", authTokenKeyEnv))
+		tokenKey = make([]byte, hmactoken.MinKeySize)
+		_, _ = rand.Read(tokenKey)
+	}
+
+	tokenSigner, err := hmactoken.New(tokenKey, authTokenTTL, hmactoken.WithRefreshLimit(authTokenRefreshLimit))
+	if err != nil {
+		log.Error("can't create auth token signer", slog.Any("error", err))
+		return
+	}
+	router.Use(restapi.Authenticate(tokenSigner, jsonResponder))
+
+	authRestHandler := authrest.New(log, tokenSigner, userUC, jsonResponder)
+	authRestHandler.SetupRoutes(router)
+
+	usersRestHandler := usersrest.New(log, userUC, jsonResponder)
+	usersRestHandler.SetupRoutes(router)
+
+	playersRestHandler := playersrest.New(log, ratingUC, gameUC, jsonResponder)
+	playersRestHandler.SetupRoutes(router)
+
+	msgpackResponder := restapi.NewMsgpackResponder(log, errConverter)
+
+	gamesRestHandler := gamesrest.New(log, gameUC, jsonResponder, jsonResponder,
+		gamesrest.WithWsCodec(gamesrest.MsgpackSubprotocol, msgpackResponder),
+		gamesrest.WithErrConverter(errConverter),
+		gamesrest.WithCheckOrigin(originPolicy.CheckOrigin),
+		gamesrest.WithMetrics(metrics),
+		gamesrest.WithWsConfig(gamesrest.WsConfig{
+			MaxMessageSize: wsMaxMessageSize,
+			WriteWait:      wsWriteWait,
+			PongWait:       wsPongWait,
+			PingPeriod:     wsPingPeriod,
+		}),
+	)
 	gamesRestHandler.SetupRoutes(router)
+	apispec.SetupRoutes(router, log)
 
 	restOpts := []restapi.Opt{
 		restapi.WithAddr(restAddr),
@@ -88,6 +203,33 @@ func main() {
 		}
 	}()
 
+	var metricsAPI *restapi.RestAPI
+	if metricsAddr := os.Getenv(metricsAddrEnv); metricsAddr != "" {
+		metricsRouter := chi.NewRouter()
+		metricsRouter.Handle(metricsPath, promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
+
+		metricsAPI, err = restapi.New(metricsRouter, log.With(slog.String("server", "metrics")),
+			restapi.WithAddr(metricsAddr),
+			restapi.WithErrorLog(slog.NewLogLogger(log.Handler(), slog.LevelError)),
+			restapi.WithReadTimeout(restReadTimeout),
+			restapi.WithWriteTimeout(restWriteTimeout),
+		)
+		if err != nil {
+			log.Error("can't create metrics server", slog.Any("error", err))
+			return
+		}
+
+		go func() {
+			err := metricsAPI.Run()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error("metrics server run error", slog.Any("error", err))
+				cancel()
+			}
+		}()
+	} else {
+		log.Info("metrics aren't served", slog.String("env", metricsAddrEnv))
+	}
+
 	<-ctx.Done()
 
 	log.Info("graceful shutdown is beginning...")
@@ -98,10 +240,23 @@ func main() {
 	done := make(chan struct{}, 1)
 
 	go func() {
-		err := restAPI.Shutdown(shutdownCtx)
+		// hijacked WebSocket connections aren't closed by the rest server shutdown
+		err := gamesRestHandler.Shutdown(shutdownCtx, reconnectAfter)
+		if err != nil {
+			log.Error("games handler shutdown error", slog.Any("error", err))
+		}
+
+		err = restAPI.Shutdown(shutdownCtx)
 		if err != nil {
 			log.Error("rest server shutdown error", slog.Any("error", err))
 		}
+
+		if metricsAPI != nil {
+			err = metricsAPI.Shutdown(shutdownCtx)
+			if err != nil {
+				log.Error("metrics server shutdown error", slog.Any("error", err))
+			}
+		}
 		close(done)
 	}()
 
@@ -112,3 +267,42 @@ func main() {
 		log.Info("graceful shutdown has completed successfully")
 	}
 }
+
+func authTokenKey() ([]byte, error) {
+	value := os.Getenv(authTokenKeyEnv)
+	if value == "" {
+		return nil, nil
+	}
+	return base64.StdEncoding.DecodeString(value)
+}
+
+// newUserRepos returns the repositories of the user data,
+// they share the database if it's configured. The returned function closes the database
+// after the pending writes, it must be called when the repositories aren't used anymore.
+func newUserRepos(ctx context.Context) (useruc.UserRepository, ratinguc.RatingRepository, func() error, error) {
+	dsn := os.Getenv(usersDBEnv)
+	if dsn == "" {
+		return mapstore.NewUserRepo(), mapstore.NewRatingRepo(), func() error { return nil }, nil
+	}
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// SQLite allows only one writer at a time
+	db.SetMaxOpenConns(1)
+
+	userRepo, err := sqlstore.NewUserRepo(ctx, db)
+	if err != nil {
+		_ = db.Close()
+		return nil, nil, nil, err
+	}
+
+	ratingRepo, err := sqlstore.NewRatingRepo(ctx, db)
+	if err != nil {
+		_ = db.Close()
+		return nil, nil, nil, err
+	}
+
+	return userRepo, ratingRepo, db.Close, nil
+}
@@ -4,16 +4,23 @@ import (
 	"context"
 	"dataxo-backend-game-ms/internal/domain"
 	"github.com/google/uuid"
+	"slices"
 	"sync"
+	"time"
 )
 
 type GameRepoMap struct {
-	m  map[uuid.UUID]*domain.Game
-	mu sync.Mutex
+	m       map[uuid.UUID]*domain.Game
+	history map[uuid.UUID][]domain.HistoryEvent
+	mu      sync.Mutex
 }
 
 func NewGameRepo() *GameRepoMap {
-	return &GameRepoMap{m: make(map[uuid.UUID]*domain.Game), mu: sync.Mutex{}}
+	return &GameRepoMap{
+		m:       make(map[uuid.UUID]*domain.Game),
+		history: make(map[uuid.UUID][]domain.HistoryEvent),
+		mu:      sync.Mutex{},
+	}
 }
 
 func (r *GameRepoMap) CreateGame(ctx context.Context, plID domain.PlayerID, side domain.Side,
@@ -28,6 +35,7 @@ func (r *GameRepoMap) CreateGame(ctx context.Context, plID domain.PlayerID, side
 
 	g := &domain.Game{
 		ID:          id,
+		CreatedAt:   time.Now(),
 		Mode:        mode,
 		Config:      cfg,
 		State:       domain.Created,
@@ -66,6 +74,59 @@ func (r *GameRepoMap) GetGame(ctx context.Context, gameID uuid.UUID) (*domain.Ga
 	return g, nil
 }
 
+// ListGames returns the filtered page of games from newest to oldest and the total count of filtered games.
+func (r *GameRepoMap) ListGames(ctx context.Context, filter domain.GameFilter) ([]*domain.Game, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	games := make([]*domain.Game, 0)
+	for _, g := range r.m {
+		if filter.State != nil && g.State != *filter.State {
+			continue
+		}
+		if g.Imported && !filter.Imported {
+			continue
+		}
+		if filter.Mode != "" && g.Mode != filter.Mode {
+			continue
+		}
+		if filter.PlayerID != (domain.PlayerID{}) && g.PlayerSide(filter.PlayerID) == domain.NoneSide {
+			continue
+		}
+		games = append(games, g)
+	}
+
+	slices.SortFunc(games, func(a, b *domain.Game) int {
+		if c := b.CreatedAt.Compare(a.CreatedAt); c != 0 {
+			return c
+		}
+		return slices.Compare(a.ID[:], b.ID[:])
+	})
+
+	total := len(games)
+
+	from := min(max(filter.Offset, 0), total)
+	to := total
+	if filter.Limit > 0 {
+		to = min(from+filter.Limit, total)
+	}
+
+	return games[from:to], total, nil
+}
+
+// CountGamesByState returns the number of games in every state.
+func (r *GameRepoMap) CountGamesByState(ctx context.Context) (map[domain.State]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := make(map[domain.State]int)
+	for _, g := range r.m {
+		counts[g.State]++
+	}
+
+	return counts, nil
+}
+
 func (r *GameRepoMap) UpdateGameState(ctx context.Context, gameID uuid.UUID, state domain.State) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -138,3 +199,37 @@ func (r *GameRepoMap) AddGamePlayer(ctx context.Context, gameID uuid.UUID, playe
 
 	return nil
 }
+
+func (r *GameRepoMap) AppendHistory(ctx context.Context, gameID uuid.UUID, events ...domain.HistoryEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.m[gameID]
+	if !ok {
+		return &domain.GameErrorWithID{
+			Err: domain.ErrNotFound,
+			ID:  gameID,
+		}
+	}
+
+	r.history[gameID] = append(r.history[gameID], events...)
+	return nil
+}
+
+func (r *GameRepoMap) GetHistory(ctx context.Context, gameID uuid.UUID) ([]domain.HistoryEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.m[gameID]
+	if !ok {
+		return nil, &domain.GameErrorWithID{
+			Err: domain.ErrNotFound,
+			ID:  gameID,
+		}
+	}
+
+	history := make([]domain.HistoryEvent, len(r.history[gameID]))
+	copy(history, r.history[gameID])
+
+	return history, nil
+}
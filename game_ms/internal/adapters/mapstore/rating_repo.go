@@ -0,0 +1,107 @@
+package mapstore
+
+import (
+	"cmp"
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"slices"
+	"sync"
+)
+
+type ratingKey struct {
+	playerID domain.PlayerID
+	pool     string
+}
+
+var leaderboards = []domain.Leaderboard{domain.RatingLeaderboard, domain.WinsLeaderboard, domain.WinStreakLeaderboard}
+
+type RatingRepoMap struct {
+	m map[ratingKey]domain.Rating
+	// ratings of the pool sorted in the order of every leaderboard,
+	// they are updated on save instead of sorting on every read
+	sorted map[string]map[domain.Leaderboard][]domain.Rating
+	mu     sync.Mutex
+}
+
+func NewRatingRepo() *RatingRepoMap {
+	return &RatingRepoMap{
+		m:      make(map[ratingKey]domain.Rating),
+		sorted: make(map[string]map[domain.Leaderboard][]domain.Rating),
+	}
+}
+
+func (r *RatingRepoMap) GetRating(ctx context.Context, playerID domain.PlayerID, pool string) (domain.Rating, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rating, ok := r.m[ratingKey{playerID: playerID, pool: pool}]
+	if !ok {
+		return domain.Rating{}, &domain.PlayerError{Err: domain.ErrNotFound, PlayerID: playerID}
+	}
+
+	return rating, nil
+}
+
+// ListRatings returns the ratings of the player sorted by pool.
+func (r *RatingRepoMap) ListRatings(ctx context.Context, playerID domain.PlayerID) ([]domain.Rating, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	ratings := make([]domain.Rating, 0)
+	for key, rating := range r.m {
+		if key.playerID == playerID {
+			ratings = append(ratings, rating)
+		}
+	}
+
+	slices.SortFunc(ratings, func(a, b domain.Rating) int {
+		return cmp.Compare(a.Pool, b.Pool)
+	})
+
+	return ratings, nil
+}
+
+// SaveRatings creates or replaces the ratings at once.
+func (r *RatingRepoMap) SaveRatings(ctx context.Context, ratings ...domain.Rating) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rating := range ratings {
+		key := ratingKey{playerID: rating.PlayerID, pool: rating.Pool}
+		old, exists := r.m[key]
+		r.m[key] = rating
+
+		sorted, ok := r.sorted[rating.Pool]
+		if !ok {
+			sorted = make(map[domain.Leaderboard][]domain.Rating)
+			r.sorted[rating.Pool] = sorted
+		}
+
+		for _, l := range leaderboards {
+			if exists {
+				i, found := slices.BinarySearchFunc(sorted[l], old, l.Compare)
+				if found {
+					sorted[l] = slices.Delete(sorted[l], i, i+1)
+				}
+			}
+
+			i, _ := slices.BinarySearchFunc(sorted[l], rating, l.Compare)
+			sorted[l] = slices.Insert(sorted[l], i, rating)
+		}
+	}
+
+	return nil
+}
+
+// TopRatings returns the ratings of the pool in the order of the leaderboard.
+func (r *RatingRepoMap) TopRatings(ctx context.Context, pool string, leaderboard domain.Leaderboard, limit, offset int) ([]domain.Rating, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sorted := r.sorted[pool][leaderboard]
+	if offset >= len(sorted) {
+		return make([]domain.Rating, 0), nil
+	}
+
+	return slices.Clone(sorted[offset:min(offset+limit, len(sorted))]), nil
+}
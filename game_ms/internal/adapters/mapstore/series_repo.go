@@ -0,0 +1,70 @@
+package mapstore
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"github.com/google/uuid"
+	"sync"
+)
+
+type SeriesRepoMap struct {
+	m  map[uuid.UUID]*domain.Series
+	mu sync.Mutex
+}
+
+func NewSeriesRepo() *SeriesRepoMap {
+	return &SeriesRepoMap{m: make(map[uuid.UUID]*domain.Series), mu: sync.Mutex{}}
+}
+
+func (r *SeriesRepoMap) CreateSeries(ctx context.Context, bestOf int, firstGameID uuid.UUID) (*domain.Series, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &domain.Series{
+		ID:      id,
+		BestOf:  bestOf,
+		State:   domain.Created,
+		Score:   make([]domain.SeriesScore, 0, 2),
+		GameIDs: []uuid.UUID{firstGameID},
+	}
+
+	r.m[s.ID] = s
+
+	return s, nil
+}
+
+func (r *SeriesRepoMap) GetSeries(ctx context.Context, seriesID uuid.UUID) (*domain.Series, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s, ok := r.m[seriesID]
+	if !ok || s == nil {
+		return nil, &domain.SeriesError{
+			Err: domain.ErrNotFound,
+			ID:  seriesID,
+		}
+	}
+
+	return s, nil
+}
+
+func (r *SeriesRepoMap) UpdateSeries(ctx context.Context, s *domain.Series) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	_, ok := r.m[s.ID]
+	if !ok {
+		return &domain.SeriesError{
+			Err: domain.ErrNotFound,
+			ID:  s.ID,
+		}
+	}
+
+	r.m[s.ID] = s
+	return nil
+}
@@ -0,0 +1,83 @@
+package mapstore
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"github.com/google/uuid"
+	"sync"
+)
+
+// UserRepoMap stores copies of the users, so callers can't change them without UpdateUser.
+type UserRepoMap struct {
+	m map[uuid.UUID]domain.User
+	// user ids by username
+	byUsername map[string]uuid.UUID
+	mu         sync.Mutex
+}
+
+func NewUserRepo() *UserRepoMap {
+	return &UserRepoMap{m: make(map[uuid.UUID]domain.User), byUsername: make(map[string]uuid.UUID)}
+}
+
+func (r *UserRepoMap) CreateUser(ctx context.Context, u *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if u.Username != "" {
+		if _, ok := r.byUsername[u.Username]; ok {
+			return domain.ErrUsernameTaken
+		}
+		r.byUsername[u.Username] = u.ID
+	}
+
+	r.m[u.ID] = *u
+	return nil
+}
+
+func (r *UserRepoMap) GetUser(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	u, ok := r.m[userID]
+	if !ok {
+		return nil, &domain.UserError{Err: domain.ErrNotFound, ID: userID}
+	}
+
+	return &u, nil
+}
+
+func (r *UserRepoMap) GetUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	id, ok := r.byUsername[username]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+
+	u := r.m[id]
+	return &u, nil
+}
+
+func (r *UserRepoMap) UpdateUser(ctx context.Context, u *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	old, ok := r.m[u.ID]
+	if !ok {
+		return &domain.UserError{Err: domain.ErrNotFound, ID: u.ID}
+	}
+
+	if u.Username != old.Username {
+		if u.Username != "" {
+			if _, ok := r.byUsername[u.Username]; ok {
+				return &domain.UserError{Err: domain.ErrUsernameTaken, ID: u.ID}
+			}
+			r.byUsername[u.Username] = u.ID
+		}
+		delete(r.byUsername, old.Username)
+	}
+
+	r.m[u.ID] = *u
+	return nil
+}
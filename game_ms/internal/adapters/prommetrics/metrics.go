@@ -0,0 +1,168 @@
+// Package prommetrics collects the metrics of the games, WebSocket sessions and HTTP requests
+// for Prometheus.
+package prommetrics
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const namespace = "dataxo"
+
+// label of the errors that aren't in restapi.DomainErrors
+const otherErrorLabel = "other"
+
+type Metrics struct {
+	moves        prometheus.Counter
+	moveDuration prometheus.Histogram
+	moveErrors   *prometheus.CounterVec
+
+	wsSessions prometheus.Gauge
+	broadcasts prometheus.Histogram
+
+	requests        *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+}
+
+// New creates the metrics and registers them.
+func New(reg prometheus.Registerer) *Metrics {
+	m := &Metrics{
+		moves: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "moves_total",
+			Help:      "Number of the made moves.",
+		}),
+		moveDuration: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "move_duration_seconds",
+			Help:      "Latency of the moves including the rejected ones.",
+			Buckets:   []float64{.0001, .00025, .0005, .001, .0025, .005, .01, .025, .05, .1},
+		}),
+		moveErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "move_errors_total",
+			Help:      "Number of the rejected moves by the domain error.",
+		}, []string{"error"}),
+
+		wsSessions: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "ws_sessions",
+			Help:      "Number of the active WebSocket sessions.",
+		}),
+		broadcasts: prometheus.NewHistogram(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "ws_broadcast_sessions",
+			Help:      "Number of the WebSocket sessions a game broadcast is sent to.",
+			Buckets:   []float64{0, 1, 2, 3, 5, 10, 25, 50, 100},
+		}),
+
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of the HTTP requests by the route and status.",
+		}, []string{"method", "route", "status"}),
+		requestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Latency of the HTTP requests except WebSocket connections.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+	}
+
+	for _, desc := range restapi.DomainErrors {
+		m.moveErrors.WithLabelValues(string(desc.Code))
+	}
+
+	reg.MustRegister(m.moves, m.moveDuration, m.moveErrors, m.wsSessions, m.broadcasts,
+		m.requests, m.requestDuration)
+	return m
+}
+
+// ObserveMove implements gameuc.Metrics.
+func (m *Metrics) ObserveMove(elapsed time.Duration, err error) {
+	m.moveDuration.Observe(elapsed.Seconds())
+	if err != nil {
+		m.moveErrors.WithLabelValues(moveErrorLabel(err)).Inc()
+		return
+	}
+	m.moves.Inc()
+}
+
+// moveErrorLabel returns the code of the domain error, the same the clients get.
+func moveErrorLabel(err error) string {
+	for _, desc := range restapi.DomainErrors {
+		if errors.Is(err, desc.Err) {
+			return string(desc.Code)
+		}
+	}
+	return otherErrorLabel
+}
+
+// WsSessionOpened implements gamesrest.Metrics.
+func (m *Metrics) WsSessionOpened() {
+	m.wsSessions.Inc()
+}
+
+// WsSessionClosed implements gamesrest.Metrics.
+func (m *Metrics) WsSessionClosed() {
+	m.wsSessions.Dec()
+}
+
+// ObserveBroadcast implements gamesrest.Metrics.
+func (m *Metrics) ObserveBroadcast(sessions int) {
+	m.broadcasts.Observe(float64(sessions))
+}
+
+// ObserveRequest implements restapi.RequestObserver.
+func (m *Metrics) ObserveRequest(method, route string, status int, elapsed time.Duration) {
+	// unmatched paths would make the label unbounded
+	if route == "" {
+		route = "unmatched"
+	}
+
+	m.requests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	// the duration of WebSocket requests is the lifetime of the connection
+	if status != http.StatusSwitchingProtocols {
+		m.requestDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+	}
+}
+
+type GameCounter interface {
+	CountGamesByState(ctx context.Context) (map[domain.State]int, error)
+}
+
+// GamesCollector reports the number of games by state when the metrics are scraped.
+type GamesCollector struct {
+	counter GameCounter
+	desc    *prometheus.Desc
+}
+
+func NewGamesCollector(counter GameCounter) *GamesCollector {
+	return &GamesCollector{
+		counter: counter,
+		desc: prometheus.NewDesc(prometheus.BuildFQName(namespace, "", "games"),
+			"Number of the games by state.", []string{"state"}, nil),
+	}
+}
+
+func (c *GamesCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *GamesCollector) Collect(ch chan<- prometheus.Metric) {
+	counts, err := c.counter.CountGamesByState(context.Background())
+	if err != nil {
+		ch <- prometheus.NewInvalidMetric(c.desc, err)
+		return
+	}
+
+	for _, state := range []domain.State{domain.Created, domain.Started, domain.Finished} {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, float64(counts[state]), state.String())
+	}
+}
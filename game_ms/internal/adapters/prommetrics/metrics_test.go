@@ -0,0 +1,86 @@
+package prommetrics
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetrics_ObserveMove(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+
+	m.ObserveMove(time.Millisecond, nil)
+	m.ObserveMove(time.Millisecond, nil)
+	m.ObserveMove(time.Millisecond, fmt.Errorf("iterate: %w", domain.ErrPlaceAlreadyTaken))
+	m.ObserveMove(time.Millisecond, &domain.GameErrorWithID{Err: domain.ErrGameFinished, ID: uuid.New()})
+	m.ObserveMove(time.Millisecond, errors.New("db is down"))
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.moves))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.moveDuration))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.moveErrors.WithLabelValues(string(restapi.CodePlaceAlreadyTaken))))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.moveErrors.WithLabelValues(string(restapi.CodeGameFinished))))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.moveErrors.WithLabelValues(otherErrorLabel)))
+	assert.Equal(t, 0.0, testutil.ToFloat64(m.moveErrors.WithLabelValues(string(restapi.CodeNotYourTurn))))
+
+	codes := make(map[restapi.ErrorCode]struct{})
+	for _, desc := range restapi.DomainErrors {
+		codes[desc.Code] = struct{}{}
+	}
+	assert.Equal(t, len(codes)+1, testutil.CollectAndCount(m.moveErrors), "labels are the domain error codes and other")
+}
+
+func TestMetrics_ObserveRequest(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+
+	m.ObserveRequest(http.MethodGet, "/api/v1/games/{game_id}", http.StatusOK, time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "/api/v1/games/{game_id}", http.StatusNotFound, time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "", http.StatusNotFound, time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "/api/v1/games/{game_id}/ws", http.StatusSwitchingProtocols, time.Hour)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues(http.MethodGet, "/api/v1/games/{game_id}", "404")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues(http.MethodGet, "unmatched", "404")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.requests.WithLabelValues(http.MethodGet, "/api/v1/games/{game_id}/ws", "101")))
+	assert.Equal(t, 2, testutil.CollectAndCount(m.requestDuration), "WebSocket connections aren't timed")
+}
+
+func TestMetrics_WsSessions(t *testing.T) {
+	m := New(prometheus.NewRegistry())
+
+	m.WsSessionOpened()
+	m.WsSessionOpened()
+	m.WsSessionClosed()
+	m.ObserveBroadcast(2)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.wsSessions))
+	assert.Equal(t, 1, testutil.CollectAndCount(m.broadcasts))
+}
+
+type gameCounter map[domain.State]int
+
+func (c gameCounter) CountGamesByState(ctx context.Context) (map[domain.State]int, error) {
+	return c, nil
+}
+
+func TestGamesCollector(t *testing.T) {
+	c := NewGamesCollector(gameCounter{domain.Started: 3, domain.Finished: 5})
+
+	expected := `
+# HELP dataxo_games Number of the games by state.
+# TYPE dataxo_games gauge
+dataxo_games{state="created"} 0
+dataxo_games{state="finished"} 5
+dataxo_games{state="started"} 3
+`
+	require.NoError(t, testutil.CollectAndCompare(c, strings.NewReader(expected)))
+}
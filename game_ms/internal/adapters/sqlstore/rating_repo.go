@@ -0,0 +1,146 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"dataxo-backend-game-ms/internal/domain"
+	"github.com/google/uuid"
+)
+
+type RatingRepoSQL struct {
+	db *sql.DB
+}
+
+// NewRatingRepo creates the ratings table if it doesn't exist.
+// Only the ratings of the users are stored, the player is identified by the user id.
+func NewRatingRepo(ctx context.Context, db *sql.DB) (*RatingRepoSQL, error) {
+	err := migrate(ctx, db,
+		`CREATE TABLE IF NOT EXISTS ratings (
+			user_id         VARCHAR(36) NOT NULL,
+			pool            VARCHAR(128) NOT NULL,
+			value           INTEGER NOT NULL,
+			games           INTEGER NOT NULL,
+			wins            INTEGER NOT NULL,
+			losses          INTEGER NOT NULL,
+			draws           INTEGER NOT NULL,
+			win_streak      INTEGER NOT NULL,
+			best_win_streak INTEGER NOT NULL,
+			updated_at      BIGINT NOT NULL,
+			PRIMARY KEY (user_id, pool)
+		)`,
+		// the leaderboards are read by the indexes without sorting
+		`CREATE INDEX IF NOT EXISTS ratings_pool_value ON ratings (pool, value DESC, updated_at, user_id)`,
+		`CREATE INDEX IF NOT EXISTS ratings_pool_wins ON ratings (pool, wins DESC, value DESC, updated_at, user_id)`,
+		`CREATE INDEX IF NOT EXISTS ratings_pool_best_win_streak
+			ON ratings (pool, best_win_streak DESC, value DESC, updated_at, user_id)`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RatingRepoSQL{db: db}, nil
+}
+
+func (r *RatingRepoSQL) GetRating(ctx context.Context, playerID domain.PlayerID, pool string) (domain.Rating, error) {
+	ratings, err := r.queryRatings(ctx, `WHERE user_id = ? AND pool = ?`, playerID.UserID.String(), pool)
+	if err != nil {
+		return domain.Rating{}, err
+	}
+	if len(ratings) == 0 {
+		return domain.Rating{}, &domain.PlayerError{Err: domain.ErrNotFound, PlayerID: playerID}
+	}
+
+	return ratings[0], nil
+}
+
+// ListRatings returns the ratings of the player sorted by pool.
+func (r *RatingRepoSQL) ListRatings(ctx context.Context, playerID domain.PlayerID) ([]domain.Rating, error) {
+	return r.queryRatings(ctx, `WHERE user_id = ? ORDER BY pool`, playerID.UserID.String())
+}
+
+// SaveRatings creates or replaces the ratings in one transaction.
+func (r *RatingRepoSQL) SaveRatings(ctx context.Context, ratings ...domain.Rating) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	for _, rating := range ratings {
+		_, err = tx.ExecContext(ctx,
+			`DELETE FROM ratings WHERE user_id = ? AND pool = ?`,
+			rating.PlayerID.UserID.String(), rating.Pool,
+		)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx,
+			`INSERT INTO ratings (user_id, pool, value, games, wins, losses, draws,
+				win_streak, best_win_streak, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			rating.PlayerID.UserID.String(), rating.Pool, rating.Value,
+			rating.Games, rating.Wins, rating.Losses, rating.Draws,
+			rating.WinStreak, rating.BestWinStreak, timeToSQL(rating.UpdatedAt),
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// leaderboard orders, they must match domain.Leaderboard.Compare
+var leaderboardOrders = map[domain.Leaderboard]string{
+	domain.RatingLeaderboard:    `ORDER BY value DESC, updated_at, user_id`,
+	domain.WinsLeaderboard:      `ORDER BY wins DESC, value DESC, updated_at, user_id`,
+	domain.WinStreakLeaderboard: `ORDER BY best_win_streak DESC, value DESC, updated_at, user_id`,
+}
+
+// TopRatings returns the ratings of the pool in the order of the leaderboard.
+func (r *RatingRepoSQL) TopRatings(ctx context.Context, pool string, leaderboard domain.Leaderboard, limit, offset int) ([]domain.Rating, error) {
+	order, ok := leaderboardOrders[leaderboard]
+	if !ok {
+		return nil, domain.ErrInvalidLeaderboard
+	}
+
+	return r.queryRatings(ctx, `WHERE pool = ? `+order+` LIMIT ? OFFSET ?`, pool, limit, offset)
+}
+
+func (r *RatingRepoSQL) queryRatings(ctx context.Context, where string, args ...any) ([]domain.Rating, error) {
+	rows, err := r.db.QueryContext(ctx,
+		`SELECT user_id, pool, value, games, wins, losses, draws, win_streak, best_win_streak, updated_at
+		FROM ratings `+where,
+		args...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	ratings := make([]domain.Rating, 0)
+	for rows.Next() {
+		var rating domain.Rating
+		var userID string
+		var updatedAt int64
+
+		err = rows.Scan(&userID, &rating.Pool, &rating.Value, &rating.Games,
+			&rating.Wins, &rating.Losses, &rating.Draws,
+			&rating.WinStreak, &rating.BestWinStreak, &updatedAt)
+		if err != nil {
+			return nil, err
+		}
+
+		id, err := uuid.Parse(userID)
+		if err != nil {
+			return nil, err
+		}
+
+		rating.PlayerID = domain.UserPlayerID(id)
+		rating.UpdatedAt = timeFromSQL(updatedAt)
+		ratings = append(ratings, rating)
+	}
+
+	return ratings, rows.Err()
+}
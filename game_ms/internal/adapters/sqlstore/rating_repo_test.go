@@ -0,0 +1,123 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"dataxo-backend-game-ms/internal/adapters/mapstore"
+	"dataxo-backend-game-ms/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestRatingRepoSQL(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	r, err := NewRatingRepo(ctx, db)
+	require.NoError(t, err)
+
+	playerID := domain.UserPlayerID(uuid.New())
+
+	_, err = r.GetRating(ctx, playerID, "b")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+
+	b := domain.NewRating(playerID, "b")
+	a := domain.NewRating(playerID, "a")
+	a.Value, a.Games, a.Wins, a.UpdatedAt = 1520, 1, 1, time.Unix(10, 0)
+	require.NoError(t, r.SaveRatings(ctx, b, a))
+
+	b.Value, b.Games, b.Draws = 1490, 1, 1
+	require.NoError(t, r.SaveRatings(ctx, b), "rating is replaced")
+
+	got, err := r.GetRating(ctx, playerID, "b")
+	require.NoError(t, err)
+	assert.Equal(t, b, got)
+
+	ratings, err := r.ListRatings(ctx, playerID)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Rating{a, b}, ratings)
+
+	ratings, err = r.ListRatings(ctx, domain.UserPlayerID(uuid.New()))
+	require.NoError(t, err)
+	assert.Empty(t, ratings)
+}
+
+// leaderboardRepo is implemented by the SQL and map repos,
+// their leaderboards must be in the order of domain.Leaderboard.Compare.
+type leaderboardRepo interface {
+	SaveRatings(ctx context.Context, ratings ...domain.Rating) error
+	TopRatings(ctx context.Context, pool string, leaderboard domain.Leaderboard, limit, offset int) ([]domain.Rating, error)
+}
+
+func TestRatingRepos_TopRatings(t *testing.T) {
+	ctx := context.Background()
+
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	sqlRepo, err := NewRatingRepo(ctx, db)
+	require.NoError(t, err)
+
+	repos := map[string]leaderboardRepo{
+		"sql": sqlRepo,
+		"map": mapstore.NewRatingRepo(),
+	}
+
+	// the narrow ranges make ties on every field of the order
+	rnd := rand.New(rand.NewPCG(1, 2))
+	now := time.Unix(1000, 0)
+	randomize := func(rating *domain.Rating) {
+		rating.Value = 1500 + 10*rnd.IntN(3)
+		rating.Wins = rnd.IntN(3)
+		rating.BestWinStreak = rnd.IntN(3)
+		rating.UpdatedAt = now.Add(time.Duration(rnd.IntN(2)) * time.Second)
+	}
+
+	ratings := make([]domain.Rating, 30)
+	for i := range ratings {
+		ratings[i] = domain.NewRating(domain.UserPlayerID(uuid.New()), "a")
+		randomize(&ratings[i])
+	}
+	other := domain.NewRating(ratings[0].PlayerID, "b")
+
+	for name, r := range repos {
+		require.NoError(t, r.SaveRatings(ctx, ratings...), name)
+		require.NoError(t, r.SaveRatings(ctx, other), name)
+	}
+
+	// the changed ratings are moved in the leaderboards
+	for i := 0; i < len(ratings); i += 3 {
+		randomize(&ratings[i])
+		for name, r := range repos {
+			require.NoError(t, r.SaveRatings(ctx, ratings[i]), name)
+		}
+	}
+
+	for _, l := range []domain.Leaderboard{domain.RatingLeaderboard, domain.WinsLeaderboard, domain.WinStreakLeaderboard} {
+		expected := slices.Clone(ratings)
+		slices.SortFunc(expected, l.Compare)
+
+		for name, r := range repos {
+			// the pages are read as the clients do
+			top := make([]domain.Rating, 0)
+			for offset := 0; offset <= len(ratings); offset += 7 {
+				page, err := r.TopRatings(ctx, "a", l, 7, offset)
+				require.NoError(t, err, name)
+				top = append(top, page...)
+			}
+
+			assert.Equal(t, expected, top, "%v repo order matches the domain one: %v", name, l)
+		}
+	}
+}
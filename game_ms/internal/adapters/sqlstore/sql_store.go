@@ -0,0 +1,33 @@
+// Package sqlstore implements the repositories on database/sql.
+// Queries use ? placeholders and portable column types, they are tested with SQLite.
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+func migrate(ctx context.Context, db *sql.DB, stmts ...string) error {
+	for _, stmt := range stmts {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// times are stored as unix nanoseconds, 0 is zero time
+func timeToSQL(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func timeFromSQL(ns int64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, ns)
+}
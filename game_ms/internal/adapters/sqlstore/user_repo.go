@@ -0,0 +1,148 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"dataxo-backend-game-ms/internal/domain"
+	"errors"
+	"github.com/google/uuid"
+)
+
+type UserRepoSQL struct {
+	db *sql.DB
+}
+
+// NewUserRepo creates the users table if it doesn't exist.
+func NewUserRepo(ctx context.Context, db *sql.DB) (*UserRepoSQL, error) {
+	err := migrate(ctx, db,
+		`CREATE TABLE IF NOT EXISTS users (
+			id            VARCHAR(36) PRIMARY KEY,
+			username      VARCHAR(32) UNIQUE,
+			display_name  VARCHAR(256) NOT NULL,
+			password_hash BLOB,
+			created_at    BIGINT NOT NULL,
+			registered_at BIGINT NOT NULL
+		)`,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UserRepoSQL{db: db}, nil
+}
+
+func (r *UserRepoSQL) CreateUser(ctx context.Context, u *domain.User) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO users (id, username, display_name, password_hash, created_at, registered_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		u.ID.String(), usernameToSQL(u.Username), u.DisplayName, u.PasswordHash,
+		timeToSQL(u.CreatedAt), timeToSQL(u.RegisteredAt),
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		return r.writeError(ctx, u, err)
+	}
+
+	return tx.Commit()
+}
+
+func (r *UserRepoSQL) GetUser(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	u, err := r.getUser(ctx, `WHERE id = ?`, userID.String())
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, &domain.UserError{Err: err, ID: userID}
+	}
+	return u, err
+}
+
+func (r *UserRepoSQL) GetUserByUsername(ctx context.Context, username string) (*domain.User, error) {
+	return r.getUser(ctx, `WHERE username = ?`, username)
+}
+
+func (r *UserRepoSQL) UpdateUser(ctx context.Context, u *domain.User) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx,
+		`UPDATE users SET username = ?, display_name = ?, password_hash = ?, created_at = ?, registered_at = ?
+		WHERE id = ?`,
+		usernameToSQL(u.Username), u.DisplayName, u.PasswordHash,
+		timeToSQL(u.CreatedAt), timeToSQL(u.RegisteredAt), u.ID.String(),
+	)
+	if err != nil {
+		_ = tx.Rollback()
+		return r.writeError(ctx, u, err)
+	}
+
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return &domain.UserError{Err: domain.ErrNotFound, ID: u.ID}
+	}
+
+	return tx.Commit()
+}
+
+func (r *UserRepoSQL) getUser(ctx context.Context, where string, args ...any) (*domain.User, error) {
+	row := r.db.QueryRowContext(ctx,
+		`SELECT id, username, display_name, password_hash, created_at, registered_at FROM users `+where, args...)
+
+	var (
+		id           string
+		username     sql.NullString
+		createdAt    int64
+		registeredAt int64
+	)
+	u := &domain.User{}
+
+	err := row.Scan(&id, &username, &u.DisplayName, &u.PasswordHash, &createdAt, &registeredAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	u.ID, err = uuid.Parse(id)
+	if err != nil {
+		return nil, err
+	}
+	u.Username = username.String
+	u.CreatedAt = timeFromSQL(createdAt)
+	u.RegisteredAt = timeFromSQL(registeredAt)
+
+	return u, nil
+}
+
+// writeError converts the error of the failed write of the user. The error of the unique
+// constraint depends on the driver, so the username is looked up after the failure,
+// the transaction must be rolled back for that.
+func (r *UserRepoSQL) writeError(ctx context.Context, u *domain.User, err error) error {
+	if u.Username == "" {
+		return err
+	}
+
+	var id string
+	lookupErr := r.db.QueryRowContext(ctx, `SELECT id FROM users WHERE username = ? AND id <> ?`,
+		u.Username, u.ID.String()).Scan(&id)
+	if lookupErr != nil {
+		return err
+	}
+
+	return &domain.UserError{Err: domain.ErrUsernameTaken, ID: u.ID}
+}
+
+// guests have NULL username, so the unique constraint doesn't apply to them
+func usernameToSQL(username string) sql.NullString {
+	return sql.NullString{String: username, Valid: username != ""}
+}
@@ -0,0 +1,67 @@
+package sqlstore
+
+import (
+	"context"
+	"database/sql"
+	"dataxo-backend-game-ms/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+	"testing"
+	"time"
+)
+
+func newTestUserRepo(t *testing.T) *UserRepoSQL {
+	db, err := sql.Open("sqlite", ":memory:")
+	require.NoError(t, err)
+	// every connection has its own in-memory database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+
+	r, err := NewUserRepo(context.Background(), db)
+	require.NoError(t, err)
+	return r
+}
+
+func TestUserRepoSQL(t *testing.T) {
+	ctx := context.Background()
+	r := newTestUserRepo(t)
+
+	guest := &domain.User{ID: uuid.New(), DisplayName: "Guest-1", CreatedAt: time.Unix(100, 5)}
+	other := &domain.User{ID: uuid.New(), DisplayName: "Guest-2", CreatedAt: time.Unix(200, 0)}
+	require.NoError(t, r.CreateUser(ctx, guest))
+	require.NoError(t, r.CreateUser(ctx, other), "guests have no username conflicts")
+
+	got, err := r.GetUser(ctx, guest.ID)
+	require.NoError(t, err)
+	assert.Equal(t, guest.ID, got.ID)
+	assert.True(t, got.IsGuest())
+	assert.True(t, got.CreatedAt.Equal(guest.CreatedAt))
+	assert.True(t, got.RegisteredAt.IsZero())
+
+	registered := *got
+	registered.Username = "alice"
+	registered.PasswordHash = []byte("hash")
+	registered.RegisteredAt = time.Unix(300, 0)
+	require.NoError(t, r.UpdateUser(ctx, &registered))
+
+	got, err = r.GetUserByUsername(ctx, "alice")
+	require.NoError(t, err)
+	assert.Equal(t, guest.ID, got.ID)
+	assert.Equal(t, []byte("hash"), got.PasswordHash)
+
+	taken := *other
+	taken.Username = "alice"
+	assert.ErrorIs(t, r.UpdateUser(ctx, &taken), domain.ErrUsernameTaken)
+
+	// the unique constraint rejects the concurrent registrations too
+	again := &domain.User{ID: uuid.New(), Username: "alice", DisplayName: "Alice", CreatedAt: time.Unix(300, 0)}
+	assert.ErrorIs(t, r.CreateUser(ctx, again), domain.ErrUsernameTaken)
+
+	_, err = r.GetUser(ctx, uuid.New())
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	_, err = r.GetUserByUsername(ctx, "bob")
+	assert.ErrorIs(t, err, domain.ErrNotFound)
+	assert.ErrorIs(t, r.UpdateUser(ctx, &domain.User{ID: uuid.New()}), domain.ErrNotFound)
+}
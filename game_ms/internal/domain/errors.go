@@ -14,11 +14,31 @@ var (
 
 	ErrGameIsNil = errors.New("game is nil")
 
+	ErrInvalidState = errors.New("invalid game state")
+
 	ErrPlaceAlreadyTaken   = errors.New("place already taken")
 	ErrMoveOutOfBoard      = errors.New("move is out of board")
 	ErrInvalidMoveInGameID = errors.New("invalid move ingame id")
 	ErrInvalidSideTurn     = errors.New("now is not your side turn")
 
+	ErrInvalidPly = errors.New("ply is out of game moves")
+
+	ErrUnsupportedMode    = errors.New("game mode is not supported")
+	ErrUnsupportedConfig  = errors.New("game config is not supported by the game mode")
+	ErrMovesAfterFinish   = errors.New("there are moves after the game finish")
+	ErrGameResultMismatch = errors.New("game result doesn't match the moves")
+	ErrMissingPlayers     = errors.New("both players must be specified")
+
+	ErrNoMoveToTakeback        = errors.New("there is no move to take back")
+	ErrLastMoveIsNotYours      = errors.New("last move is not yours")
+	ErrTakebackAlreadyProposed = errors.New("takeback already proposed")
+	ErrTakebackNotProposed     = errors.New("takeback not proposed")
+	ErrCantAnswerOwnTakeback   = errors.New("can't answer own takeback")
+	ErrDrawAlreadyOffered      = errors.New("draw already offered")
+	ErrDrawNotOffered          = errors.New("draw not offered")
+	ErrCantAnswerOwnDrawOffer  = errors.New("can't answer own draw offer")
+	ErrPlayerIsNotInGame       = errors.New("player is not in game")
+
 	ErrAlreadyJoined         = errors.New("already joined")
 	ErrAllPlacesAlreadyTaken = errors.New("all places already taken in this game")
 	ErrNotEnoughPlayers      = errors.New("not enough players")
@@ -27,6 +47,19 @@ var (
 	ErrNegativeOrZeroedWinLineLength = errors.New("win line length is negative or equals to zero")
 	ErrNegativeOrZeroedBoardWidth    = errors.New("board width is negative or equals to zero")
 	ErrNegativeOrZeroedBoardHeight   = errors.New("board height is negative or equals to zero")
+
+	ErrInvalidSeriesBestOf = errors.New("series best of must be an odd number from 1 to 7")
+	ErrSeriesFinished      = errors.New("series finished")
+
+	ErrInvalidUsername    = errors.New("username must be 3-32 latin letters, digits or underscores")
+	ErrWeakPassword       = errors.New("password must be 8-72 bytes long")
+	ErrInvalidDisplayName = errors.New("display name must be 1-64 characters long")
+	ErrUsernameTaken      = errors.New("username already taken")
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrAlreadyRegistered  = errors.New("user already registered")
+
+	ErrInvalidLeaderboard = errors.New("leaderboard must be one of rating, wins or win_streak")
+	ErrMissingRatingPool  = errors.New("rating pool must be specified")
 )
 
 func IsNeedReSync(err error) bool {
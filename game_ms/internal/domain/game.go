@@ -3,18 +3,32 @@ package domain
 import (
 	"fmt"
 	"github.com/google/uuid"
+	"slices"
+	"time"
 )
 
 type Game struct {
-	ID          uuid.UUID
-	Mode        string
-	Config      DisappearingModeConfig
-	State       State
-	Moves       []Move
-	XPlayer     *Player
-	OPlayer     *Player
-	WinSequence []Move
-	Winner      WinSide
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	Mode         string
+	Config       DisappearingModeConfig
+	State        State
+	Moves        []Move
+	XPlayer      *Player
+	OPlayer      *Player
+	WinSequence  []Move
+	Winner       WinSide
+	FinishReason FinishReason
+	// uuid.Nil if the game is not a part of series
+	SeriesID uuid.UUID
+	// NoneSide if there is no pending takeback proposal
+	TakebackProposedBy Side
+	// NoneSide if there is no pending draw offer
+	DrawOfferedBy Side
+	// the game result changes the ratings of the players
+	Rated bool
+	// the game is imported from the notation, so its result isn't trusted
+	Imported bool
 }
 
 type GameErrorWithID struct {
@@ -32,6 +46,16 @@ func (e *GameErrorWithID) Unwrap() error {
 
 const ModeWithFriend = "with-friend"
 
+// Modes are the supported game modes.
+var Modes = []string{ModeWithFriend}
+
+func ValidateMode(mode string) error {
+	if !slices.Contains(Modes, mode) {
+		return ErrUnsupportedMode
+	}
+	return nil
+}
+
 type State int
 
 const (
@@ -53,6 +77,54 @@ func (s State) String() string {
 	}
 }
 
+type FinishReason int
+
+const (
+	NotFinished FinishReason = iota
+	WinLineFinish
+	NoPlaceFinish
+	DrawAgreementFinish
+	ResignationFinish
+)
+
+func (r FinishReason) String() string {
+	switch r {
+	case NotFinished:
+		return "not_finished"
+	case WinLineFinish:
+		return "win_line"
+	case NoPlaceFinish:
+		return "no_place"
+	case DrawAgreementFinish:
+		return "draw_agreement"
+	case ResignationFinish:
+		return "resignation"
+	default:
+		return "invalid"
+	}
+}
+
+func ParseState(s string) (State, error) {
+	for _, state := range []State{Created, Started, Finished} {
+		if state.String() == s {
+			return state, nil
+		}
+	}
+	return Created, ErrInvalidState
+}
+
+// GameFilter is used to list games. Zero values of fields mean no filtering.
+type GameFilter struct {
+	State *State
+	Mode  string
+	// games the player has played on any side
+	PlayerID PlayerID
+	// imported games are listed only if set
+	Imported bool
+	Limit    int
+	Offset   int
+}
+
 type Move struct {
 	ID        int  `json:"id"`
 	InGameID  int  `json:"in_game_id"`
@@ -97,6 +169,13 @@ func (e *MoveErrorWithInGameID) Unwrap() error {
 
 type PlayerID struct {
 	ClientID string
+	// nil for players that aren't users, e.g. of the imported games
+	UserID uuid.UUID
+}
+
+// UserPlayerID returns the player id of the user, client id is the user id.
+func UserPlayerID(userID uuid.UUID) PlayerID {
+	return PlayerID{ClientID: userID.String(), UserID: userID}
 }
 
 type Player struct {
@@ -136,6 +215,9 @@ func (e *AddGamePlayerError) Unwrap() error {
 
 type ModeParams struct {
 	MySide SideRequest
+	// 0 or 1 is a single game without series
+	BestOf int
+	Rated  bool
 }
 
 type SideRequest int
@@ -168,6 +250,26 @@ func (s Side) ToWinSide() WinSide {
 	}
 }
 
+// SideByInGameID returns side of the move by its number,
+// because X always makes the first move and sides alternate.
+func SideByInGameID(inGameID int) Side {
+	if inGameID%2 == 0 {
+		return XSide
+	}
+	return OSide
+}
+
+func (s Side) Opponent() Side {
+	switch s {
+	case XSide:
+		return OSide
+	case OSide:
+		return XSide
+	default:
+		return NoneSide
+	}
+}
+
 func NoneSideMove() Move {
 	return Move{Side: NoneSide}
 }
@@ -216,4 +318,10 @@ type MoveEvent struct {
 type MakeMoveResult struct {
 	GameFinished bool
 	Events       []MoveEvent
+	Winner       WinSide
+	FinishReason FinishReason
+	// uuid.Nil if there is no next game in series
+	NextGameID uuid.UUID
+	// empty if the game isn't rated
+	RatingChanges []RatingChange
 }
@@ -0,0 +1,58 @@
+package domain
+
+import "time"
+
+type HistoryEventKind int
+
+const (
+	MoveHistoryEvent HistoryEventKind = iota
+	DrawAgreementHistoryEvent
+	ResignationHistoryEvent
+)
+
+func (k HistoryEventKind) String() string {
+	switch k {
+	case MoveHistoryEvent:
+		return "move"
+	case DrawAgreementHistoryEvent:
+		return "draw_agreement"
+	case ResignationHistoryEvent:
+		return "resignation"
+	default:
+		return "invalid"
+	}
+}
+
+// HistoryEvent is an entry of the append-only game log.
+// Taken back moves are not removed from the log,
+// their compensating events are appended instead.
+type HistoryEvent struct {
+	Kind HistoryEventKind
+	// number of the move the event belongs to, starting from 1,
+	// the events without move have the number of the last move
+	MoveNumber int
+	// Type and Move are set for MoveHistoryEvent only
+	Type     MoveEventType
+	Move     Move
+	Actor    PlayerID
+	Takeback bool
+	Time     time.Time
+}
+
+func NewHistoryEvents(events []MoveEvent, moveNumber int, actor PlayerID, takeback bool, t time.Time) []HistoryEvent {
+	history := make([]HistoryEvent, len(events))
+
+	for i := range events {
+		history[i] = HistoryEvent{
+			Kind:       MoveHistoryEvent,
+			MoveNumber: moveNumber,
+			Type:       events[i].Type,
+			Move:       events[i].Move,
+			Actor:      actor,
+			Takeback:   takeback,
+			Time:       t,
+		}
+	}
+
+	return history
+}
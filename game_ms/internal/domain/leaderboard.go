@@ -0,0 +1,67 @@
+package domain
+
+import "cmp"
+
+// Leaderboard is the order of the ratings of the pool.
+type Leaderboard int
+
+const (
+	RatingLeaderboard Leaderboard = iota
+	WinsLeaderboard
+	WinStreakLeaderboard
+)
+
+func (l Leaderboard) String() string {
+	switch l {
+	case RatingLeaderboard:
+		return "rating"
+	case WinsLeaderboard:
+		return "wins"
+	case WinStreakLeaderboard:
+		return "win_streak"
+	default:
+		return "invalid"
+	}
+}
+
+func ParseLeaderboard(s string) (Leaderboard, error) {
+	for _, l := range []Leaderboard{RatingLeaderboard, WinsLeaderboard, WinStreakLeaderboard} {
+		if l.String() == s {
+			return l, nil
+		}
+	}
+	return RatingLeaderboard, ErrInvalidLeaderboard
+}
+
+// Score returns the value the leaderboard is ordered by.
+func (l Leaderboard) Score(r Rating) int {
+	switch l {
+	case WinsLeaderboard:
+		return r.Wins
+	case WinStreakLeaderboard:
+		return r.BestWinStreak
+	default:
+		return r.Value
+	}
+}
+
+// Compare orders the ratings from the top of the leaderboard. Ties are broken by
+// the rating value, then by who has reached it first, so the order is total.
+func (l Leaderboard) Compare(a, b Rating) int {
+	if c := cmp.Compare(l.Score(b), l.Score(a)); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(b.Value, a.Value); c != 0 {
+		return c
+	}
+	if c := a.UpdatedAt.Compare(b.UpdatedAt); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.PlayerID.ClientID, b.PlayerID.ClientID)
+}
+
+type LeaderboardEntry struct {
+	// starts from 1
+	Rank   int
+	Rating Rating
+}
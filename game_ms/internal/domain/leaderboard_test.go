@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseLeaderboard(t *testing.T) {
+	for _, l := range []Leaderboard{RatingLeaderboard, WinsLeaderboard, WinStreakLeaderboard} {
+		parsed, err := ParseLeaderboard(l.String())
+		require.NoError(t, err)
+		assert.Equal(t, l, parsed)
+	}
+
+	_, err := ParseLeaderboard("losses")
+	assert.ErrorIs(t, err, ErrInvalidLeaderboard)
+}
+
+func TestLeaderboard_Compare(t *testing.T) {
+	now := time.Now()
+	a := Rating{PlayerID: PlayerID{ClientID: "a"}, Value: 1600, Wins: 3, BestWinStreak: 1, UpdatedAt: now}
+	b := Rating{PlayerID: PlayerID{ClientID: "b"}, Value: 1550, Wins: 5, BestWinStreak: 3, UpdatedAt: now}
+	c := Rating{PlayerID: PlayerID{ClientID: "c"}, Value: 1550, Wins: 5, BestWinStreak: 3, UpdatedAt: now.Add(-time.Hour)}
+	d := Rating{PlayerID: PlayerID{ClientID: "d"}, Value: 1550, Wins: 5, BestWinStreak: 3, UpdatedAt: now}
+
+	tests := map[Leaderboard][]Rating{
+		RatingLeaderboard:    {a, c, b, d},
+		WinsLeaderboard:      {c, b, d, a},
+		WinStreakLeaderboard: {c, b, d, a},
+	}
+
+	for l, expected := range tests {
+		ratings := []Rating{d, b, a, c}
+		slices.SortFunc(ratings, l.Compare)
+		assert.Equal(t, expected, ratings, l.String())
+	}
+}
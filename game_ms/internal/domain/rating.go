@@ -0,0 +1,108 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"math"
+	"time"
+)
+
+const (
+	InitialRating = 1500
+
+	// games after which the rating isn't provisional anymore
+	ProvisionalGames = 30
+
+	provisionalEloK = 40
+	eloK            = 20
+)
+
+// Rating is the Elo rating of the player in the pool of the same mode and board preset.
+type Rating struct {
+	PlayerID PlayerID
+	Pool     string
+	Value    int
+	Games    int
+	Wins     int
+	Losses   int
+	Draws    int
+	// current series of wins in a row
+	WinStreak     int
+	BestWinStreak int
+	UpdatedAt     time.Time
+}
+
+type RatingChange struct {
+	PlayerID PlayerID
+	Pool     string
+	Before   int
+	After    int
+}
+
+func (c RatingChange) Delta() int {
+	return c.After - c.Before
+}
+
+func NewRating(playerID PlayerID, pool string) Rating {
+	return Rating{PlayerID: playerID, Pool: pool, Value: InitialRating}
+}
+
+// RatingPool returns the pool of the games that are rated together.
+func RatingPool(mode string, cfg DisappearingModeConfig) string {
+	return fmt.Sprintf("%v:%vx%v:line%v:limit%v",
+		mode, cfg.BoardWidth, cfg.BoardHeight, cfg.WinLineLength, cfg.PlayerFiguresLimit)
+}
+
+func (r *Rating) IsProvisional() bool {
+	return r.Games < ProvisionalGames
+}
+
+// EloExpectedScore returns the expected score of the player against the opponent.
+func EloExpectedScore(rating, opponentRating int) float64 {
+	return 1 / (1 + math.Pow(10, float64(opponentRating-rating)/400))
+}
+
+// Apply updates the rating by the game result against the opponent,
+// score is 1 for a win, 0.5 for a draw and 0 for a loss.
+func (r *Rating) Apply(opponentRating int, score float64, now time.Time) RatingChange {
+	k := float64(eloK)
+	if r.IsProvisional() {
+		k = provisionalEloK
+	}
+
+	change := RatingChange{PlayerID: r.PlayerID, Pool: r.Pool, Before: r.Value}
+
+	r.Value += int(math.Round(k * (score - EloExpectedScore(r.Value, opponentRating))))
+	r.Games++
+	switch score {
+	case 1:
+		r.Wins++
+		r.WinStreak++
+		r.BestWinStreak = max(r.BestWinStreak, r.WinStreak)
+	case 0:
+		r.Losses++
+		r.WinStreak = 0
+	default:
+		r.Draws++
+		r.WinStreak = 0
+	}
+	r.UpdatedAt = now
+
+	change.After = r.Value
+	return change
+}
+
+// IsRatable reports whether the finished game can change the ratings:
+// it must be rated and played by two different users.
+func (g *Game) IsRatable() bool {
+	if !g.Rated || g.State != Finished || g.XPlayer == nil || g.OPlayer == nil {
+		return false
+	}
+
+	x, o := g.XPlayer.ID.UserID, g.OPlayer.ID.UserID
+	if x == uuid.Nil || o == uuid.Nil || x == o {
+		return false
+	}
+
+	return g.Winner == XWin || g.Winner == OWin || g.Winner == Draw
+}
@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRating_Apply(t *testing.T) {
+	now := time.Now()
+
+	a := NewRating(PlayerID{ClientID: "a"}, "pool")
+	b := NewRating(PlayerID{ClientID: "b"}, "pool")
+
+	change := a.Apply(b.Value, 1, now)
+	assert.Equal(t, RatingChange{PlayerID: a.PlayerID, Pool: "pool", Before: 1500, After: 1520}, change)
+	assert.Equal(t, 20, change.Delta())
+	assert.Equal(t, 1, a.Wins)
+
+	change = b.Apply(1500, 0, now)
+	assert.Equal(t, -20, change.Delta())
+	assert.Equal(t, 1, b.Losses)
+
+	// the stronger player gains less for the win and loses more for the draw
+	strong := Rating{Value: 1900, Games: ProvisionalGames}
+	assert.Equal(t, 2, strong.Apply(1500, 1, now).Delta())
+	assert.Equal(t, -8, strong.Apply(1500, 0.5, now).Delta())
+	assert.Equal(t, 1, strong.Draws)
+}
+
+func TestRating_ApplyWinStreak(t *testing.T) {
+	now := time.Now()
+	r := NewRating(PlayerID{ClientID: "a"}, "pool")
+
+	for _, score := range []float64{1, 1, 0.5, 1, 0, 1} {
+		r.Apply(1500, score, now)
+	}
+	assert.Equal(t, 1, r.WinStreak)
+	assert.Equal(t, 2, r.BestWinStreak, "draw breaks the streak")
+}
+
+func TestGame_IsRatable(t *testing.T) {
+	x := UserPlayerID(uuid.New())
+	o := UserPlayerID(uuid.New())
+
+	g := &Game{Rated: true, State: Finished, Winner: Draw,
+		XPlayer: &Player{ID: x}, OPlayer: &Player{ID: o}}
+	assert.True(t, g.IsRatable())
+
+	g.Rated = false
+	assert.False(t, g.IsRatable())
+
+	g.Rated = true
+	g.OPlayer = &Player{ID: PlayerID{ClientID: "imported"}}
+	assert.False(t, g.IsRatable(), "players must be users")
+
+	g.OPlayer = &Player{ID: x}
+	assert.False(t, g.IsRatable(), "players must be different")
+}
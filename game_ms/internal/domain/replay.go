@@ -0,0 +1,11 @@
+package domain
+
+// BoardSnapshot is the board state after the first Ply moves of the game.
+type BoardSnapshot struct {
+	Ply int
+	// figures on the board ordered by rows
+	Cells    []Move
+	NextSide Side
+	// nil if no figure disappears on the next move
+	NextToDisappear *Move
+}
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+)
+
+const MaxSeriesBestOf = 7
+
+type Series struct {
+	ID      uuid.UUID
+	BestOf  int
+	State   State
+	Score   []SeriesScore
+	Draws   int
+	GameIDs []uuid.UUID
+	// nil until the series is finished, stays nil if the series is drawn
+	Winner *PlayerID
+}
+
+type SeriesScore struct {
+	PlayerID PlayerID
+	Wins     int
+}
+
+type SeriesError struct {
+	Err error
+	ID  uuid.UUID
+}
+
+func (e *SeriesError) Error() string {
+	return fmt.Sprintf("series with id %v: %v", e.ID, e.Err)
+}
+
+func (e *SeriesError) Unwrap() error {
+	return e.Err
+}
+
+func ValidateSeriesBestOf(bestOf int) error {
+	if bestOf <= 0 || bestOf > MaxSeriesBestOf || bestOf%2 == 0 {
+		return ErrInvalidSeriesBestOf
+	}
+	return nil
+}
+
+// WinsNeeded returns the count of game wins that decides the series.
+func (s *Series) WinsNeeded() int {
+	return s.BestOf/2 + 1
+}
+
+// MaxGames returns the count of games after which the series is decided even
+// if nobody has got enough wins, so the draws can't prolong it forever.
+func (s *Series) MaxGames() int {
+	return 2 * s.BestOf
+}
+
+// Played returns the count of the recorded games.
+func (s *Series) Played() int {
+	played := s.Draws
+	for i := range s.Score {
+		played += s.Score[i].Wins
+	}
+	return played
+}
+
+func (s *Series) Wins(playerID PlayerID) int {
+	for i := range s.Score {
+		if s.Score[i].PlayerID == playerID {
+			return s.Score[i].Wins
+		}
+	}
+	return 0
+}
+
+func (s *Series) AddPlayer(playerID PlayerID) {
+	for i := range s.Score {
+		if s.Score[i].PlayerID == playerID {
+			return
+		}
+	}
+	s.Score = append(s.Score, SeriesScore{PlayerID: playerID})
+}
+
+// RecordGame counts the result of the finished series game and finishes
+// the series when one of the players has got enough wins or MaxGames are played.
+func (s *Series) RecordGame(g *Game) error {
+	if s.State == Finished {
+		return &SeriesError{Err: ErrSeriesFinished, ID: s.ID}
+	}
+	if g.XPlayer == nil || g.OPlayer == nil {
+		return &SeriesError{Err: ErrNotEnoughPlayers, ID: s.ID}
+	}
+
+	s.AddPlayer(g.XPlayer.ID)
+	s.AddPlayer(g.OPlayer.ID)
+	s.State = Started
+
+	var winner PlayerID
+	switch g.Winner {
+	case XWin:
+		winner = g.XPlayer.ID
+	case OWin:
+		winner = g.OPlayer.ID
+	case Draw:
+		s.Draws++
+		s.finishIfMaxGamesPlayed()
+		return nil
+	default:
+		return &SeriesError{Err: ErrInvalidWinSide, ID: s.ID}
+	}
+
+	for i := range s.Score {
+		if s.Score[i].PlayerID != winner {
+			continue
+		}
+
+		s.Score[i].Wins++
+		if s.Score[i].Wins >= s.WinsNeeded() {
+			s.State = Finished
+			s.Winner = &winner
+			return nil
+		}
+	}
+
+	s.finishIfMaxGamesPlayed()
+	return nil
+}
+
+// finishIfMaxGamesPlayed gives the series to the player with more wins,
+// the series is drawn if the wins are equal.
+func (s *Series) finishIfMaxGamesPlayed() {
+	if s.Played() < s.MaxGames() {
+		return
+	}
+
+	s.State = Finished
+
+	var leader *SeriesScore
+	tie := false
+	for i := range s.Score {
+		switch {
+		case leader == nil || s.Score[i].Wins > leader.Wins:
+			leader, tie = &s.Score[i], false
+		case s.Score[i].Wins == leader.Wins:
+			tie = true
+		}
+	}
+
+	if leader != nil && !tie {
+		winner := leader.PlayerID
+		s.Winner = &winner
+	}
+}
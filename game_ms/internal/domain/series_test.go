@@ -0,0 +1,105 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSeries_RecordGame(t *testing.T) {
+	alice := PlayerID{ClientID: "alice"}
+	bob := PlayerID{ClientID: "bob"}
+
+	tcases := []struct {
+		Name    string
+		BestOf  int
+		Winners []WinSide
+		State   State
+		Winner  *PlayerID
+		Draws   int
+	}{
+		{
+			Name:    "best of 3 not decided",
+			BestOf:  3,
+			Winners: []WinSide{XWin},
+			State:   Started,
+		},
+		{
+			Name:    "best of 3 won by x",
+			BestOf:  3,
+			Winners: []WinSide{XWin, XWin},
+			State:   Finished,
+			Winner:  &alice,
+		},
+		{
+			Name:    "draws are not counted as wins",
+			BestOf:  3,
+			Winners: []WinSide{Draw, OWin, Draw, OWin},
+			State:   Finished,
+			Winner:  &bob,
+			Draws:   2,
+		},
+		{
+			Name:    "draws end the series after max games",
+			BestOf:  1,
+			Winners: []WinSide{Draw, Draw},
+			State:   Finished,
+			Draws:   2,
+		},
+		{
+			Name:    "leader wins the series after max games",
+			BestOf:  3,
+			Winners: []WinSide{XWin, Draw, Draw, Draw, Draw, Draw},
+			State:   Finished,
+			Winner:  &alice,
+			Draws:   5,
+		},
+		{
+			Name:    "equal wins draw the series after max games",
+			BestOf:  3,
+			Winners: []WinSide{XWin, OWin, Draw, Draw, Draw, Draw},
+			State:   Finished,
+			Draws:   4,
+		},
+		{
+			Name:    "best of 3 continues before max games",
+			BestOf:  3,
+			Winners: []WinSide{Draw, Draw, Draw, Draw, Draw},
+			State:   Started,
+			Draws:   5,
+		},
+		{
+			Name:    "best of 5 is not decided by two wins",
+			BestOf:  5,
+			Winners: []WinSide{OWin, OWin, XWin},
+			State:   Started,
+		},
+	}
+
+	for _, tc := range tcases {
+		s := &Series{BestOf: tc.BestOf}
+
+		for _, winner := range tc.Winners {
+			g := &Game{
+				XPlayer: &Player{ID: alice},
+				OPlayer: &Player{ID: bob},
+				Winner:  winner,
+			}
+			assert.NoError(t, s.RecordGame(g), tc.Name)
+		}
+
+		assert.Equal(t, tc.State, s.State, tc.Name)
+		assert.Equal(t, tc.Winner, s.Winner, tc.Name)
+		assert.Equal(t, tc.Draws, s.Draws, tc.Name)
+	}
+}
+
+func TestSeries_RecordGameAfterFinish(t *testing.T) {
+	s := &Series{BestOf: 1, State: Finished}
+	g := &Game{
+		XPlayer: &Player{ID: PlayerID{ClientID: "alice"}},
+		OPlayer: &Player{ID: PlayerID{ClientID: "bob"}},
+		Winner:  XWin,
+	}
+
+	assert.ErrorIs(t, s.RecordGame(g), ErrSeriesFinished)
+}
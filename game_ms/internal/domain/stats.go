@@ -0,0 +1,143 @@
+package domain
+
+// Outcome is the result of the finished game for one of the players.
+type Outcome int
+
+const (
+	NoOutcome Outcome = iota
+	WinOutcome
+	LossOutcome
+	DrawOutcome
+)
+
+func (o Outcome) String() string {
+	switch o {
+	case WinOutcome:
+		return "win"
+	case LossOutcome:
+		return "loss"
+	case DrawOutcome:
+		return "draw"
+	default:
+		return "none"
+	}
+}
+
+// PlayerSide returns the side of the player in the game, NoneSide if it isn't a player of the game.
+func (g *Game) PlayerSide(playerID PlayerID) Side {
+	switch {
+	case g.XPlayer != nil && g.XPlayer.ID == playerID:
+		return XSide
+	case g.OPlayer != nil && g.OPlayer.ID == playerID:
+		return OSide
+	default:
+		return NoneSide
+	}
+}
+
+// Opponent returns the player of the opposite side, nil if there is no such player yet.
+func (g *Game) Opponent(side Side) *Player {
+	switch side {
+	case XSide:
+		return g.OPlayer
+	case OSide:
+		return g.XPlayer
+	default:
+		return nil
+	}
+}
+
+// Outcome returns the result of the game for the side.
+func (g *Game) Outcome(side Side) Outcome {
+	switch {
+	case side == NoneSide || g.Winner == NoneWin:
+		return NoOutcome
+	case g.Winner == Draw:
+		return DrawOutcome
+	case g.Winner == side.ToWinSide():
+		return WinOutcome
+	default:
+		return LossOutcome
+	}
+}
+
+// IsDisappearingWin reports whether the win line has reused a place
+// which was freed by a disappeared figure.
+func (g *Game) IsDisappearingWin() bool {
+	if g.FinishReason != WinLineFinish {
+		return false
+	}
+
+	for _, move := range g.WinSequence {
+		if move.TimesUsed > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// PlayerStats is the summary of the finished games of the player.
+type PlayerStats struct {
+	Games  int
+	Wins   int
+	Losses int
+	Draws  int
+	// plies of all the games
+	Moves int
+	// games where the player has made the first move, i.e. played X
+	FirstMoveGames int
+	FirstMoveWins  int
+	// wins by the win line with a place freed by a disappeared figure
+	DisappearingWins int
+}
+
+// Add counts the finished game, the games the player hasn't played are skipped.
+func (s *PlayerStats) Add(g *Game, playerID PlayerID) {
+	side := g.PlayerSide(playerID)
+	outcome := g.Outcome(side)
+	if g.State != Finished || outcome == NoOutcome {
+		return
+	}
+
+	s.Games++
+	s.Moves += len(g.Moves)
+
+	switch outcome {
+	case WinOutcome:
+		s.Wins++
+		if g.IsDisappearingWin() {
+			s.DisappearingWins++
+		}
+	case LossOutcome:
+		s.Losses++
+	case DrawOutcome:
+		s.Draws++
+	}
+
+	if side == XSide {
+		s.FirstMoveGames++
+		if outcome == WinOutcome {
+			s.FirstMoveWins++
+		}
+	}
+}
+
+func (s *PlayerStats) AverageMoves() float64 {
+	return ratio(s.Moves, s.Games)
+}
+
+func (s *PlayerStats) FirstMoveWinRate() float64 {
+	return ratio(s.FirstMoveWins, s.FirstMoveGames)
+}
+
+// DisappearingWinRate returns the share of the wins with a disappeared figure.
+func (s *PlayerStats) DisappearingWinRate() float64 {
+	return ratio(s.DisappearingWins, s.Wins)
+}
+
+func ratio(a, b int) float64 {
+	if b == 0 {
+		return 0
+	}
+	return float64(a) / float64(b)
+}
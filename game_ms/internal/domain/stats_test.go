@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPlayerStats_Add(t *testing.T) {
+	me := PlayerID{ClientID: "me"}
+	other := PlayerID{ClientID: "other"}
+
+	game := func(x, o PlayerID, winner WinSide, reason FinishReason, moves int, winSequence ...Move) *Game {
+		return &Game{State: Finished, XPlayer: &Player{ID: x}, OPlayer: &Player{ID: o},
+			Winner: winner, FinishReason: reason, Moves: make([]Move, moves), WinSequence: winSequence}
+	}
+
+	var stats PlayerStats
+	for _, g := range []*Game{
+		game(me, other, XWin, WinLineFinish, 7, Move{TimesUsed: 1}, Move{TimesUsed: 2}),
+		game(me, other, OWin, ResignationFinish, 4),
+		game(other, me, OWin, WinLineFinish, 10, Move{TimesUsed: 1}),
+		game(other, me, Draw, DrawAgreementFinish, 3),
+		game(other, PlayerID{ClientID: "third"}, XWin, WinLineFinish, 5),
+		{State: Started, XPlayer: &Player{ID: me}},
+	} {
+		stats.Add(g, me)
+	}
+
+	assert.Equal(t, PlayerStats{Games: 4, Wins: 2, Losses: 1, Draws: 1, Moves: 24,
+		FirstMoveGames: 2, FirstMoveWins: 1, DisappearingWins: 1}, stats)
+	assert.Equal(t, 6.0, stats.AverageMoves())
+	assert.Equal(t, 0.5, stats.FirstMoveWinRate())
+	assert.Equal(t, 0.5, stats.DisappearingWinRate())
+
+	assert.Zero(t, (&PlayerStats{}).AverageMoves(), "no games")
+}
+
+func TestGame_Outcome(t *testing.T) {
+	g := &Game{Winner: OWin}
+	assert.Equal(t, LossOutcome, g.Outcome(XSide))
+	assert.Equal(t, WinOutcome, g.Outcome(OSide))
+	assert.Equal(t, NoOutcome, g.Outcome(NoneSide))
+
+	g.Winner = Draw
+	assert.Equal(t, DrawOutcome, g.Outcome(XSide))
+}
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	// in characters
+	MinPasswordLength = 8
+	// in bytes, bcrypt ignores the bytes after 72
+	MaxPasswordLength = 72
+
+	MaxDisplayNameLength = 64
+)
+
+var usernameRegexp = regexp.MustCompile(`^[a-z0-9_]{3,32}$`)
+
+// User is a guest minted by the server or a registered account.
+type User struct {
+	ID uuid.UUID
+	// lowercase, empty for guests
+	Username     string
+	DisplayName  string
+	PasswordHash []byte
+	CreatedAt    time.Time
+	// zero for guests
+	RegisteredAt time.Time
+}
+
+type UserError struct {
+	Err error
+	ID  uuid.UUID
+}
+
+func (e *UserError) Error() string {
+	return fmt.Sprintf("user with id %v: %v", e.ID, e.Err)
+}
+
+func (e *UserError) Unwrap() error {
+	return e.Err
+}
+
+func (u *User) IsGuest() bool {
+	return u.Username == ""
+}
+
+func (u *User) PlayerID() PlayerID {
+	return UserPlayerID(u.ID)
+}
+
+// GuestDisplayName is the display name of the new guest.
+func GuestDisplayName(id uuid.UUID) string {
+	return "Guest-" + id.String()[:8]
+}
+
+// NormalizeUsername makes usernames case-insensitive.
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// ValidateUsername validates the normalized username.
+func ValidateUsername(username string) error {
+	if !usernameRegexp.MatchString(username) {
+		return ErrInvalidUsername
+	}
+	return nil
+}
+
+func ValidatePassword(password string) error {
+	if utf8.RuneCountInString(password) < MinPasswordLength || len(password) > MaxPasswordLength {
+		return ErrWeakPassword
+	}
+	return nil
+}
+
+func ValidateDisplayName(name string) error {
+	n := utf8.RuneCountInString(strings.TrimSpace(name))
+	if n == 0 || n > MaxDisplayNameLength {
+		return ErrInvalidDisplayName
+	}
+	return nil
+}
@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestValidatePassword(t *testing.T) {
+	tests := map[string]error{
+		"secret1":                   ErrWeakPassword,
+		"secret12":                  nil,
+		"пароль":                    ErrWeakPassword,
+		"парольчик":                 nil,
+		strings.Repeat("a", 72):     nil,
+		strings.Repeat("a", 73):     ErrWeakPassword,
+		strings.Repeat("пароль", 7): ErrWeakPassword,
+	}
+
+	for password, expected := range tests {
+		assert.ErrorIs(t, ValidatePassword(password), expected, password)
+	}
+}
@@ -0,0 +1,33 @@
+// Package apispec serves the machine-readable API documents: OpenAPI for
+// the REST routes and AsyncAPI for the WebSocket messages.
+package apispec
+
+import (
+	_ "embed"
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+	"net/http"
+)
+
+//go:embed openapi.json
+var OpenAPI []byte
+
+//go:embed asyncapi.json
+var AsyncAPI []byte
+
+func SetupRoutes(r chi.Router, log *slog.Logger) {
+	r.Get("/api/v1/openapi.json", serveJSON(OpenAPI, log))
+	r.Get("/api/v1/asyncapi.json", serveJSON(AsyncAPI, log))
+}
+
+func serveJSON(data []byte, log *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+
+		_, err := w.Write(data)
+		if err != nil {
+			log.Error("api spec: write response", slog.Any("error", err))
+		}
+	}
+}
@@ -0,0 +1,375 @@
+package apispec
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"dataxo-backend-game-ms/internal/ports/restapi/authrest"
+	"dataxo-backend-game-ms/internal/ports/restapi/gamesrest"
+	"dataxo-backend-game-ms/internal/ports/restapi/playersrest"
+	"dataxo-backend-game-ms/internal/ports/restapi/usersrest"
+	"encoding"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+type schema struct {
+	Type       string             `json:"type"`
+	Ref        string             `json:"$ref"`
+	Properties map[string]*schema `json:"properties"`
+	Items      *schema            `json:"items"`
+	Enum       []string           `json:"enum"`
+}
+
+type spec struct {
+	Paths      map[string]map[string]json.RawMessage `json:"paths"`
+	Channels   map[string]*channel                   `json:"channels"`
+	Components struct {
+		Schemas  map[string]*schema `json:"schemas"`
+		Messages map[string]*struct {
+			Name string `json:"name"`
+		} `json:"messages"`
+	} `json:"components"`
+}
+
+type channel struct {
+	Publish struct {
+		Message struct {
+			OneOf []*schema `json:"oneOf"`
+		} `json:"message"`
+	} `json:"publish"`
+}
+
+var restSchemas = map[string]interface{}{
+	"HTTPError":            restapi.HTTPError{},
+	"TokenResp":            authrest.TokenResp{},
+	"LoginReq":             authrest.LoginReq{},
+	"RegisterReq":          usersrest.RegisterReq{},
+	"UpdateProfileReq":     usersrest.UpdateProfileReq{},
+	"UserResp":             usersrest.UserResp{},
+	"ModeParams":           gamesrest.ModeParams{},
+	"CreateWithFriendReq":  gamesrest.CreateWithFriendReq{},
+	"CreateWithFriendResp": gamesrest.CreateWithFriendResp{},
+	"GameResp":             gamesrest.GameResp{},
+	"ListGamesResp":        gamesrest.ListGamesResp{},
+	"HistoryEvent":         gamesrest.HistoryEvent{},
+	"HistoryResp":          gamesrest.HistoryResp{},
+	"BoardResp":            gamesrest.BoardResp{},
+	"ImportGameResp":       gamesrest.ImportGameResp{},
+	"MakeMoveReq":          gamesrest.WsGameReq{},
+	"MakeMoveResp":         gamesrest.MakeMoveResp{},
+	"Move":                 domain.Move{},
+	"MoveEvent":            gamesrest.MoveEvent{},
+	"WsGameConfig":         gamesrest.WsGameConfig{},
+	"GamePlayer":           gamesrest.GamePlayer{},
+	"GamePlayers":          gamesrest.GamePlayers{},
+	"SeriesScore":          gamesrest.SeriesScore{},
+	"SeriesResp":           gamesrest.SeriesResp{},
+	"RatingChangeResp":     gamesrest.RatingChangeResp{},
+	"RatingResp":           playersrest.RatingResp{},
+	"PlayerRatingResp":     playersrest.PlayerRatingResp{},
+	"LeaderboardEntryResp": playersrest.LeaderboardEntryResp{},
+	"LeaderboardResp":      playersrest.LeaderboardResp{},
+	"PlayerGameResp":       playersrest.PlayerGameResp{},
+	"PlayerGamesResp":      playersrest.PlayerGamesResp{},
+	"PlayerStatsResp":      playersrest.PlayerStatsResp{},
+}
+
+// handlerPackages are the directories of the packages whose JSON types must have schemas.
+var handlerPackages = []string{"../authrest", "../usersrest", "../gamesrest", "../playersrest"}
+
+// undocumentedTypes are the JSON types of the handler packages that have no schema of their own.
+var undocumentedTypes = []string{
+	// its fields are checked as part of the broadcasts that embed it
+	"gamesrest.WsBroadcastMeta",
+	// readiness messages are not routed yet
+	"gamesrest.WsReadinessReq",
+}
+
+var wsSchemas = map[string]interface{}{
+	"WsMuxReq":                    gamesrest.WsMuxReq{},
+	"WsHelloReq":                  gamesrest.WsHelloReq{},
+	"WsHelloResp":                 gamesrest.WsHelloResp{},
+	"WsGameReq":                   gamesrest.WsGameReq{},
+	"WsPresenceReq":               gamesrest.WsPresenceReq{},
+	"WsTakebackReq":               gamesrest.WsTakebackReq{},
+	"WsError":                     gamesrest.WsError{},
+	"WsSideResponse":              gamesrest.WsSideResponse{},
+	"WsCreateResponse":            gamesrest.WsCreateResponse{},
+	"WsGameStateResp":             gamesrest.WsGameStateResp{},
+	"WsSyncReq":                   gamesrest.WsSyncReq{},
+	"WsSyncResp":                  gamesrest.WsSyncResp{},
+	"GameResp":                    gamesrest.GameResp{},
+	"WsGameResp":                  gamesrest.WsGameResp{},
+	"WsSuccessResp":               gamesrest.WsSuccessResp{},
+	"WsMoveBroadcast":             gamesrest.WsMoveBroadcast{},
+	"WsGameStartBroadcast":        gamesrest.WsGameStartBroadcast{},
+	"WsPresenceBroadcast":         gamesrest.WsPresenceBroadcast{},
+	"WsServerShutdownMessage":     gamesrest.WsServerShutdownMessage{},
+	"WsGameFinishBroadcast":       gamesrest.WsGameFinishBroadcast{},
+	"WsTakebackProposalBroadcast": gamesrest.WsTakebackProposalBroadcast{},
+	"WsTakebackDeclineBroadcast":  gamesrest.WsTakebackDeclineBroadcast{},
+	"WsTakebackBroadcast":         gamesrest.WsTakebackBroadcast{},
+	"WsDrawOfferBroadcast":        gamesrest.WsDrawOfferBroadcast{},
+	"WsDrawDeclineBroadcast":      gamesrest.WsDrawDeclineBroadcast{},
+	"Move":                        domain.Move{},
+	"MoveEvent":                   gamesrest.MoveEvent{},
+	"WsGameConfig":                gamesrest.WsGameConfig{},
+	"GamePlayer":                  gamesrest.GamePlayer{},
+	"GamePlayers":                 gamesrest.GamePlayers{},
+	"SeriesScore":                 gamesrest.SeriesScore{},
+	"SeriesResp":                  gamesrest.SeriesResp{},
+	"RatingChangeResp":            gamesrest.RatingChangeResp{},
+}
+
+func TestOpenAPIMatchesTypes(t *testing.T) {
+	testSpecMatchesTypes(t, OpenAPI, restSchemas)
+}
+
+func TestAsyncAPIMatchesTypes(t *testing.T) {
+	testSpecMatchesTypes(t, AsyncAPI, wsSchemas)
+}
+
+func TestSpecsCoverTypes(t *testing.T) {
+	documented := make(map[string]bool)
+	for _, name := range undocumentedTypes {
+		documented[name] = true
+	}
+	for _, types := range []map[string]interface{}{restSchemas, wsSchemas} {
+		for _, v := range types {
+			typ := reflect.TypeOf(v)
+			documented[path.Base(typ.PkgPath())+"."+typ.Name()] = true
+		}
+	}
+
+	for _, dir := range handlerPackages {
+		for _, name := range jsonTypes(t, dir) {
+			assert.True(t, documented[name], "%v has no schema", name)
+		}
+	}
+}
+
+func TestAsyncAPIMatchesWsRoutes(t *testing.T) {
+	routes := make([]string, 0)
+	for _, route := range gamesrest.New(nil, nil, nil, nil).WsRoutes() {
+		routes = append(routes, route.Type)
+	}
+
+	s := &spec{}
+	require.NoError(t, json.Unmarshal(AsyncAPI, s))
+
+	documented := make([]string, 0)
+	for _, ch := range s.Channels {
+		for _, ref := range ch.Publish.Message.OneOf {
+			msg, ok := s.Components.Messages[strings.TrimPrefix(ref.Ref, "#/components/messages/")]
+			if assert.True(t, ok, "message %v", ref.Ref) {
+				documented = append(documented, msg.Name)
+			}
+		}
+	}
+
+	assert.ElementsMatch(t, routes, documented, "WebSocket routes and published messages")
+}
+
+func TestOpenAPIMatchesRoutes(t *testing.T) {
+	router := chi.NewRouter()
+	authrest.New(nil, nil, nil, nil).SetupRoutes(router)
+	usersrest.New(nil, nil, nil).SetupRoutes(router)
+	playersrest.New(nil, nil, nil, nil).SetupRoutes(router)
+	gamesrest.New(nil, nil, nil, nil).SetupRoutes(router)
+	SetupRoutes(router, nil)
+
+	routes := make([]string, 0)
+	err := chi.Walk(router, func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		routes = append(routes, strings.ToLower(method)+" "+route)
+		return nil
+	})
+	require.NoError(t, err)
+
+	s := &spec{}
+	require.NoError(t, json.Unmarshal(OpenAPI, s))
+
+	documented := make([]string, 0)
+	for path, operations := range s.Paths {
+		for method := range operations {
+			documented = append(documented, method+" "+path)
+		}
+	}
+
+	assert.ElementsMatch(t, routes, documented, "router routes and spec paths")
+}
+
+func TestErrorCodesMatchCatalogue(t *testing.T) {
+	codes := make([]string, 0)
+	for _, code := range restapi.NewErrConverter(gamesrest.Errors...).Codes() {
+		codes = append(codes, string(code))
+	}
+
+	for name, data := range map[string][]byte{"HTTPError": OpenAPI, "WsError": AsyncAPI} {
+		s := &spec{}
+		require.NoError(t, json.Unmarshal(data, s))
+
+		assert.ElementsMatch(t, codes, s.Components.Schemas[name].Properties["code"].Enum, "codes of %v", name)
+	}
+}
+
+func testSpecMatchesTypes(t *testing.T, data []byte, types map[string]interface{}) {
+	s := &spec{}
+	require.NoError(t, json.Unmarshal(data, s))
+
+	assert.ElementsMatch(t, keys(types), keys(s.Components.Schemas), "spec schemas and Go types")
+
+	for name, v := range types {
+		sc, ok := s.Components.Schemas[name]
+		if !ok {
+			continue
+		}
+
+		fields := jsonFields(reflect.TypeOf(v))
+		assert.ElementsMatch(t, keys(fields), keys(sc.Properties), "properties of %v", name)
+
+		for field, typ := range fields {
+			prop, ok := sc.Properties[field]
+			if !ok {
+				continue
+			}
+			assert.Equal(t, typ, schemaType(prop), "type of %v.%v", name, field)
+		}
+	}
+}
+
+// jsonTypes returns qualified names of the exported struct types
+// that have JSON tags, declared in the package directory.
+func jsonTypes(t *testing.T, dir string) []string {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	require.NoError(t, err)
+
+	res := make([]string, 0)
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+
+		f, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.SkipObjectResolution)
+		require.NoError(t, err)
+
+		ast.Inspect(f, func(n ast.Node) bool {
+			spec, ok := n.(*ast.TypeSpec)
+			if !ok || !spec.Name.IsExported() {
+				return true
+			}
+			st, ok := spec.Type.(*ast.StructType)
+			if !ok {
+				return true
+			}
+
+			for _, field := range st.Fields.List {
+				if field.Tag != nil && strings.Contains(field.Tag.Value, "json:") {
+					res = append(res, f.Name.Name+"."+spec.Name.Name)
+					break
+				}
+			}
+			return true
+		})
+	}
+
+	return res
+}
+
+// jsonFields returns JSON names of the struct fields with their JSON schema types.
+func jsonFields(t reflect.Type) map[string]string {
+	fields := make(map[string]string)
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			for k, v := range jsonFields(f.Type) {
+				fields[k] = v
+			}
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = jsonType(f.Type)
+	}
+
+	return fields
+}
+
+var (
+	textMarshaler = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshaler = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	timeType      = reflect.TypeOf(time.Time{})
+)
+
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType, t.Implements(textMarshaler):
+		return "string"
+	case t.Implements(jsonMarshaler):
+		// raw messages and other custom encodings have any type
+		return ""
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Struct, reflect.Map:
+		return "object"
+	default:
+		return ""
+	}
+}
+
+func schemaType(s *schema) string {
+	if s.Ref != "" {
+		return "object"
+	}
+	return s.Type
+}
+
+func keys[V any](m map[string]V) []string {
+	res := make([]string, 0, len(m))
+	for k := range m {
+		res = append(res, k)
+	}
+	sort.Strings(res)
+	return res
+}
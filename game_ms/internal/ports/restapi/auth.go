@@ -0,0 +1,94 @@
+package restapi
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/pkg/hmactoken"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"strings"
+)
+
+var (
+	ErrMissingToken = errors.New("missing auth token")
+	ErrInvalidToken = errors.New("invalid auth token")
+)
+
+type TokenError struct {
+	Err error
+}
+
+func (e *TokenError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrInvalidToken, e.Err)
+}
+
+func (e *TokenError) Is(target error) bool {
+	return target == ErrInvalidToken
+}
+
+func (e *TokenError) Unwrap() error {
+	return e.Err
+}
+
+type TokenVerifier interface {
+	Verify(token string) (hmactoken.Claims, error)
+}
+
+type playerIDKey struct{}
+
+// TokenQueryParam is used by WebSocket and EventSource clients that can't set headers.
+const TokenQueryParam = "access_token"
+
+// Authenticate puts the player of the request token into the context.
+// Requests without token are passed as is, requests with invalid one are rejected.
+func Authenticate(verifier TokenVerifier, responder Responder) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := RequestToken(r)
+			if token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			claims, err := verifier.Verify(token)
+			if err != nil {
+				responder.RespondError(w, r, http.StatusUnauthorized, &TokenError{Err: err})
+				return
+			}
+
+			// the subject is the user id
+			userID, err := uuid.Parse(claims.Subject)
+			if err != nil {
+				responder.RespondError(w, r, http.StatusUnauthorized, &TokenError{Err: err})
+				return
+			}
+
+			ctx := WithPlayerID(r.Context(), domain.UserPlayerID(userID))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequestToken returns the bearer token of Authorization header or access_token query param.
+func RequestToken(r *http.Request) string {
+	if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+		return strings.TrimSpace(token)
+	}
+	return r.URL.Query().Get(TokenQueryParam)
+}
+
+func WithPlayerID(ctx context.Context, playerID domain.PlayerID) context.Context {
+	return context.WithValue(ctx, playerIDKey{}, playerID)
+}
+
+// PlayerIDFromContext returns the authenticated player,
+// the error is ErrMissingToken if the request hasn't got a token.
+func PlayerIDFromContext(ctx context.Context) (domain.PlayerID, error) {
+	playerID, ok := ctx.Value(playerIDKey{}).(domain.PlayerID)
+	if !ok {
+		return domain.PlayerID{}, ErrMissingToken
+	}
+	return playerID, nil
+}
@@ -0,0 +1,68 @@
+package restapi
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/pkg/hmactoken"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAuthenticate(t *testing.T) {
+	signer, err := hmactoken.New([]byte("0123456789abcdef0123456789abcdef"), time.Hour)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	token, _, err := signer.Issue(userID.String())
+	require.NoError(t, err)
+
+	handler := Authenticate(signer, NewJsonResponder(nil, nil))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		playerID, err := PlayerIDFromContext(r.Context())
+		if err != nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_, _ = w.Write([]byte(playerID.ClientID))
+	}))
+
+	serve := func(r *http.Request) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	w := serve(r)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, userID.String(), w.Body.String())
+
+	w = serve(httptest.NewRequest(http.MethodGet, "/?access_token="+token, nil))
+	assert.Equal(t, userID.String(), w.Body.String())
+
+	w = serve(httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusNoContent, w.Code, "request without token is passed")
+
+	w = serve(httptest.NewRequest(http.MethodGet, "/?access_token="+token+"x", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	httpErr := &HTTPError{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), httpErr))
+	assert.Equal(t, CodeInvalidToken, httpErr.Code)
+}
+
+func TestPlayerIDFromContext(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	_, err := PlayerIDFromContext(r.Context())
+	assert.ErrorIs(t, err, ErrMissingToken)
+
+	playerID, err := PlayerIDFromContext(WithPlayerID(r.Context(), domain.PlayerID{ClientID: "bob"}))
+	require.NoError(t, err)
+	assert.Equal(t, domain.PlayerID{ClientID: "bob"}, playerID)
+}
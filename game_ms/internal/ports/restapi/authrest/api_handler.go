@@ -0,0 +1,48 @@
+package authrest
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"dataxo-backend-game-ms/pkg/hmactoken"
+	"dataxo-backend-game-ms/pkg/slogdiscard"
+	"github.com/google/uuid"
+	"log/slog"
+)
+
+type TokenIssuer interface {
+	Issue(subject string) (string, hmactoken.Claims, error)
+	// Refresh issues the token of the same subject until the refresh limit
+	Refresh(token string) (string, hmactoken.Claims, error)
+}
+
+type UserUsecase interface {
+	CreateGuest(ctx context.Context) (*domain.User, error)
+	GetUser(ctx context.Context, userID uuid.UUID) (*domain.User, error)
+	Login(ctx context.Context, username, password string) (*domain.User, error)
+}
+
+type Handler struct {
+	log       *slog.Logger
+	issuer    TokenIssuer
+	userUC    UserUsecase
+	responder restapi.Responder
+
+	rateLimits RateLimits
+	limiters   *rateLimiters
+}
+
+type Opt func(h *Handler)
+
+func New(log *slog.Logger, issuer TokenIssuer, userUC UserUsecase, responder restapi.Responder, opts ...Opt) *Handler {
+	log = slogdiscard.LoggerIfNil(log)
+	h := &Handler{log: log, issuer: issuer, userUC: userUC, responder: responder,
+		rateLimits: DefaultRateLimits()}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.limiters = newRateLimiters(h.rateLimits)
+	return h
+}
@@ -0,0 +1,8 @@
+package authrest
+
+import "github.com/go-chi/chi/v5"
+
+func (h *Handler) SetupRoutes(r chi.Router) {
+	r.Post("/api/v1/auth/tokens", h.IssueToken())
+	r.Post("/api/v1/auth/login", h.Login())
+}
@@ -0,0 +1,58 @@
+package authrest
+
+import (
+	"dataxo-backend-game-ms/pkg/ratelimit"
+	"net"
+	"net/http"
+)
+
+// RateLimits limits the clients by their IP addresses, zero limits aren't applied.
+type RateLimits struct {
+	// guest creations of the IP, token refreshes aren't limited
+	Guests ratelimit.Limit
+	// login attempts of the IP
+	Logins ratelimit.Limit
+}
+
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		Guests: ratelimit.Limit{Rate: 0.05, Burst: 10},
+		Logins: ratelimit.Limit{Rate: 0.1, Burst: 10},
+	}
+}
+
+// WithRateLimits replaces DefaultRateLimits.
+func WithRateLimits(limits RateLimits) Opt {
+	return func(h *Handler) {
+		h.rateLimits = limits
+	}
+}
+
+type rateLimiters struct {
+	guests *ratelimit.Limiter[string]
+	logins *ratelimit.Limiter[string]
+}
+
+func newRateLimiters(limits RateLimits) *rateLimiters {
+	return &rateLimiters{
+		guests: ratelimit.New[string](limits.Guests),
+		logins: ratelimit.New[string](limits.Logins),
+	}
+}
+
+func (h *Handler) allowGuest(r *http.Request) bool {
+	return h.limiters.guests.Allow(remoteIP(r))
+}
+
+func (h *Handler) allowLogin(r *http.Request) bool {
+	return h.limiters.logins.Allow(remoteIP(r))
+}
+
+// remoteIP returns the IP of the client, the ports of its connections differ.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,62 @@
+package authrest
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"dataxo-backend-game-ms/pkg/hmactoken"
+	"dataxo-backend-game-ms/pkg/ratelimit"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type guestUserUC struct {
+	UserUsecase
+	guests int
+}
+
+func (uc *guestUserUC) CreateGuest(ctx context.Context) (*domain.User, error) {
+	uc.guests++
+	return &domain.User{ID: uuid.New()}, nil
+}
+
+func (uc *guestUserUC) Login(ctx context.Context, username, password string) (*domain.User, error) {
+	return nil, domain.ErrInvalidCredentials
+}
+
+func TestHandler_RateLimits(t *testing.T) {
+	signer, err := hmactoken.New([]byte(strings.Repeat("k", hmactoken.MinKeySize)), time.Hour)
+	require.NoError(t, err)
+
+	userUC := &guestUserUC{}
+	h := New(nil, signer, userUC, restapi.NewJsonResponder(nil, nil), WithRateLimits(RateLimits{
+		Guests: ratelimit.Limit{Rate: 0.001, Burst: 2},
+		Logins: ratelimit.Limit{Rate: 0.001, Burst: 1},
+	}))
+
+	request := func(handler http.HandlerFunc, path, addr, body string) int {
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		r.RemoteAddr = addr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	issueToken := h.IssueToken()
+	assert.Equal(t, http.StatusCreated, request(issueToken, "/api/v1/auth/tokens", "10.0.0.1:1000", ""))
+	assert.Equal(t, http.StatusCreated, request(issueToken, "/api/v1/auth/tokens", "10.0.0.1:1001", ""))
+	assert.Equal(t, http.StatusTooManyRequests, request(issueToken, "/api/v1/auth/tokens", "10.0.0.1:1002", ""))
+	assert.Equal(t, http.StatusCreated, request(issueToken, "/api/v1/auth/tokens", "10.0.0.2:1000", ""), "other IP")
+	assert.Equal(t, 3, userUC.guests)
+
+	login := h.Login()
+	body := `{"username":"alice","password":"password"}`
+	assert.Equal(t, http.StatusUnauthorized, request(login, "/api/v1/auth/login", "10.0.0.1:1000", body))
+	assert.Equal(t, http.StatusTooManyRequests, request(login, "/api/v1/auth/login", "10.0.0.1:1001", body))
+}
@@ -0,0 +1,115 @@
+package authrest
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"dataxo-backend-game-ms/pkg/hmactoken"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type TokenResp struct {
+	Token     string    `json:"token"`
+	ClientID  string    `json:"client_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Guest     bool      `json:"guest"`
+}
+
+type LoginReq struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// IssueToken mints a guest and issues its token, or refreshes the token
+// if the request already has a valid one of an existing user.
+// Tokens aren't refreshed after the refresh limit since the login or the guest creation.
+func (h *Handler) IssueToken() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		u, err := h.currentUser(r)
+		if err == nil {
+			token, claims, err := h.issuer.Refresh(restapi.RequestToken(r))
+			if err != nil {
+				log.Info("refresh token", slog.Any("error", err))
+				h.responder.RespondError(w, r, http.StatusUnauthorized, &restapi.TokenError{Err: err})
+				return
+			}
+
+			h.writeToken(w, u, token, claims)
+			return
+		}
+
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, restapi.ErrMissingToken) {
+			if !h.allowGuest(r) {
+				h.responder.RespondError(w, r, http.StatusTooManyRequests, restapi.ErrRateLimited)
+				return
+			}
+			u, err = h.userUC.CreateGuest(ctx)
+		}
+		if err != nil {
+			log.Error("issue token: get user", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		h.respondToken(w, r, u)
+	}
+}
+
+func (h *Handler) Login() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !h.allowLogin(r) {
+			h.responder.RespondError(w, r, http.StatusTooManyRequests, restapi.ErrRateLimited)
+			return
+		}
+
+		req := &LoginReq{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u, err := h.userUC.Login(r.Context(), req.Username, req.Password)
+		if err != nil {
+			log.Info("uc login", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusUnauthorized, err)
+			return
+		}
+
+		h.respondToken(w, r, u)
+	}
+}
+
+func (h *Handler) currentUser(r *http.Request) (*domain.User, error) {
+	playerID, err := restapi.PlayerIDFromContext(r.Context())
+	if err != nil {
+		return nil, err
+	}
+	return h.userUC.GetUser(r.Context(), playerID.UserID)
+}
+
+func (h *Handler) respondToken(w http.ResponseWriter, r *http.Request, u *domain.User) {
+	token, claims, err := h.issuer.Issue(u.ID.String())
+	if err != nil {
+		h.log.Error("issue token", slog.Any("error", err))
+		h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	h.writeToken(w, u, token, claims)
+}
+
+func (h *Handler) writeToken(w http.ResponseWriter, u *domain.User, token string, claims hmactoken.Claims) {
+	h.responder.Respond(w, http.StatusCreated, &TokenResp{
+		Token:     token,
+		ClientID:  claims.Subject,
+		ExpiresAt: time.Unix(claims.ExpiresAt, 0).UTC(),
+		Guest:     u.IsGuest(),
+	})
+}
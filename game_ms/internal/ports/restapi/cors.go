@@ -0,0 +1,111 @@
+package restapi
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrOriginNotAllowed = errors.New("origin not allowed")
+
+// AnyOrigin allows requests of every origin.
+const AnyOrigin = "*"
+
+var (
+	corsAllowedMethods = []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	corsAllowedHeaders = []string{"Authorization", "Content-Type", "Accept", "Accept-Language", "X-Request-Id"}
+
+	corsMaxAge = 10 * time.Minute
+)
+
+// OriginPolicy decides which origins of the browsers can use the API.
+// The requests without Origin and from the origin of the API itself are always allowed.
+type OriginPolicy struct {
+	origins map[string]struct{}
+	any     bool
+}
+
+// NewOriginPolicy allows the origins like "https://example.com" or any of them with AnyOrigin.
+func NewOriginPolicy(origins ...string) *OriginPolicy {
+	p := &OriginPolicy{origins: make(map[string]struct{}, len(origins))}
+	for _, origin := range origins {
+		origin = strings.TrimRight(strings.ToLower(strings.TrimSpace(origin)), "/")
+		switch origin {
+		case "":
+		case AnyOrigin:
+			p.any = true
+		default:
+			p.origins[origin] = struct{}{}
+		}
+	}
+	return p
+}
+
+// IsAllowed reports whether the origin may access the API of the request host.
+func (p *OriginPolicy) IsAllowed(origin string, r *http.Request) bool {
+	if origin == "" || p.any {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if _, ok := p.origins[origin]; ok {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	return err == nil && strings.EqualFold(u.Host, r.Host)
+}
+
+// CheckOrigin is used by WebSocket upgraders, browsers don't apply CORS to WebSocket handshakes.
+func (p *OriginPolicy) CheckOrigin(r *http.Request) bool {
+	return p.IsAllowed(r.Header.Get("Origin"), r)
+}
+
+// CORS adds the CORS headers to the responses for the allowed origins and answers preflight requests.
+// Preflight requests of other origins are rejected, other requests are passed without the headers,
+// so the browsers don't expose the responses to the pages.
+func CORS(policy *OriginPolicy, responder Responder) func(http.Handler) http.Handler {
+	allowedMethods := strings.Join(corsAllowedMethods, ", ")
+	allowedHeaders := strings.Join(corsAllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(corsMaxAge.Seconds()))
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+
+			w.Header().Add("Vary", "Origin")
+
+			if !policy.IsAllowed(origin, r) {
+				if preflight {
+					responder.RespondError(w, r, http.StatusForbidden, ErrOriginNotAllowed)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("Access-Control-Allow-Origin", origin)
+
+			if !preflight {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Access-Control-Request-Method")
+			w.Header().Add("Vary", "Access-Control-Request-Headers")
+			w.Header().Set("Access-Control-Allow-Methods", allowedMethods)
+			w.Header().Set("Access-Control-Allow-Headers", allowedHeaders)
+			w.Header().Set("Access-Control-Max-Age", maxAge)
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
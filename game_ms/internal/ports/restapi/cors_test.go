@@ -0,0 +1,105 @@
+package restapi
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOriginPolicy_CheckOrigin(t *testing.T) {
+	policy := NewOriginPolicy("https://play.example.com/", " HTTPS://Admin.example.com", "")
+
+	tests := map[string]bool{
+		"":                          true,
+		"https://play.example.com":  true,
+		"https://admin.example.com": true,
+		"http://api.example.com":    true,
+		"https://evil.example.com":  false,
+		"http://play.example.com":   false,
+		"null":                      false,
+	}
+
+	for origin, expected := range tests {
+		r := httptest.NewRequest(http.MethodGet, "http://api.example.com/api/v1/games/create/ws", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		assert.Equal(t, expected, policy.CheckOrigin(r), origin)
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Origin", "https://evil.example.com")
+	assert.True(t, NewOriginPolicy(AnyOrigin).CheckOrigin(r))
+}
+
+func TestCORS(t *testing.T) {
+	handler := CORS(NewOriginPolicy("https://play.example.com"), NewJsonResponder(nil, nil))(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusTeapot)
+		}))
+
+	serve := func(method, origin string, preflight bool) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "http://api.example.com/api/v1/games", nil)
+		if origin != "" {
+			r.Header.Set("Origin", origin)
+		}
+		if preflight {
+			r.Header.Set("Access-Control-Request-Method", http.MethodPost)
+			r.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+		}
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	t.Run("allowed preflight", func(t *testing.T) {
+		w := serve(http.MethodOptions, "https://play.example.com", true)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "https://play.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+		assert.Contains(t, w.Header().Get("Access-Control-Allow-Methods"), http.MethodPost)
+		assert.Contains(t, w.Header().Get("Access-Control-Allow-Headers"), "Authorization")
+		assert.NotEmpty(t, w.Header().Get("Access-Control-Max-Age"))
+		assert.Contains(t, w.Header().Values("Vary"), "Origin")
+	})
+
+	t.Run("rejected preflight", func(t *testing.T) {
+		w := serve(http.MethodOptions, "https://evil.example.com", true)
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+
+		httpErr := &HTTPError{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), httpErr))
+		assert.Equal(t, CodeOriginNotAllowed, httpErr.Code)
+	})
+
+	t.Run("allowed request", func(t *testing.T) {
+		w := serve(http.MethodPost, "https://play.example.com", false)
+		assert.Equal(t, http.StatusTeapot, w.Code)
+		assert.Equal(t, "https://play.example.com", w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("rejected request is passed without headers", func(t *testing.T) {
+		w := serve(http.MethodPost, "https://evil.example.com", false)
+		assert.Equal(t, http.StatusTeapot, w.Code)
+		assert.Empty(t, w.Header().Get("Access-Control-Allow-Origin"))
+	})
+
+	t.Run("options without preflight headers", func(t *testing.T) {
+		w := serve(http.MethodOptions, "https://play.example.com", false)
+		assert.Equal(t, http.StatusTeapot, w.Code)
+	})
+
+	t.Run("same origin", func(t *testing.T) {
+		w := serve(http.MethodOptions, "http://api.example.com", true)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+	})
+
+	t.Run("no origin", func(t *testing.T) {
+		w := serve(http.MethodGet, "", false)
+		assert.Equal(t, http.StatusTeapot, w.Code)
+		assert.Empty(t, w.Header().Values("Vary"))
+	})
+}
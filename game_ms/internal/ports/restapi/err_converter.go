@@ -1 +1,307 @@
 package restapi
+
+import (
+	"cmp"
+	"dataxo-backend-game-ms/internal/domain"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrorCode is the stable identifier of the error for the clients.
+// Unlike the error text it is never changed.
+type ErrorCode string
+
+const (
+	CodeInternal       ErrorCode = "internal"
+	CodeBadRequest     ErrorCode = "bad_request"
+	CodeUnauthorized   ErrorCode = "unauthorized"
+	CodeForbidden      ErrorCode = "forbidden"
+	CodeNotFound       ErrorCode = "not_found"
+	CodeConflict       ErrorCode = "conflict"
+	CodeUnprocessable  ErrorCode = "unprocessable"
+	CodeNotImplemented ErrorCode = "not_implemented"
+
+	CodeInvalidSide    ErrorCode = "invalid_side"
+	CodeInvalidWinSide ErrorCode = "invalid_win_side"
+	CodeInvalidState   ErrorCode = "invalid_state"
+	CodeInvalidPly     ErrorCode = "invalid_ply"
+	CodeInvalidConfig  ErrorCode = "invalid_config"
+
+	CodeGameNotStarted     ErrorCode = "game_not_started"
+	CodeGameAlreadyStarted ErrorCode = "game_already_started"
+	CodeGameFinished       ErrorCode = "game_finished"
+
+	CodePlaceAlreadyTaken ErrorCode = "place_already_taken"
+	CodeMoveOutOfBoard    ErrorCode = "move_out_of_board"
+	CodeInvalidMoveID     ErrorCode = "invalid_move_id"
+	CodeNotYourTurn       ErrorCode = "not_your_turn"
+
+	CodeUnsupportedMode    ErrorCode = "unsupported_mode"
+	CodeUnsupportedConfig  ErrorCode = "unsupported_config"
+	CodeMovesAfterFinish   ErrorCode = "moves_after_finish"
+	CodeGameResultMismatch ErrorCode = "game_result_mismatch"
+	CodeMissingPlayers     ErrorCode = "missing_players"
+
+	CodeNoMoveToTakeback        ErrorCode = "no_move_to_takeback"
+	CodeLastMoveIsNotYours      ErrorCode = "last_move_is_not_yours"
+	CodeTakebackAlreadyProposed ErrorCode = "takeback_already_proposed"
+	CodeTakebackNotProposed     ErrorCode = "takeback_not_proposed"
+	CodeCantAnswerOwnTakeback   ErrorCode = "cant_answer_own_takeback"
+	CodeDrawAlreadyOffered      ErrorCode = "draw_already_offered"
+	CodeDrawNotOffered          ErrorCode = "draw_not_offered"
+	CodeCantAnswerOwnDrawOffer  ErrorCode = "cant_answer_own_draw_offer"
+	CodePlayerIsNotInGame       ErrorCode = "player_is_not_in_game"
+
+	CodeAlreadyJoined         ErrorCode = "already_joined"
+	CodeAllPlacesAlreadyTaken ErrorCode = "all_places_already_taken"
+	CodeNotEnoughPlayers      ErrorCode = "not_enough_players"
+
+	CodeInvalidSeriesBestOf ErrorCode = "invalid_series_best_of"
+	CodeSeriesFinished      ErrorCode = "series_finished"
+
+	CodeInvalidUsername    ErrorCode = "invalid_username"
+	CodeWeakPassword       ErrorCode = "weak_password"
+	CodeInvalidDisplayName ErrorCode = "invalid_display_name"
+	CodeUsernameTaken      ErrorCode = "username_taken"
+	CodeInvalidCredentials ErrorCode = "invalid_credentials"
+	CodeAlreadyRegistered  ErrorCode = "already_registered"
+
+	CodeInvalidLeaderboard ErrorCode = "invalid_leaderboard"
+	CodeMissingRatingPool  ErrorCode = "missing_rating_pool"
+
+	CodeWrongMessageType           ErrorCode = "wrong_message_type"
+	CodeUnsupportedProtocolVersion ErrorCode = "unsupported_protocol_version"
+	CodeFeatureNotNegotiated       ErrorCode = "feature_not_negotiated"
+	CodeInvalidAction              ErrorCode = "invalid_action"
+	CodeInvalidPagination          ErrorCode = "invalid_pagination"
+	CodeMissingToken               ErrorCode = "missing_token"
+	CodeInvalidToken               ErrorCode = "invalid_token"
+	CodeOriginNotAllowed           ErrorCode = "origin_not_allowed"
+	CodeRateLimited                ErrorCode = "rate_limited"
+	CodeShuttingDown               ErrorCode = "shutting_down"
+	CodeNotAPlayer                 ErrorCode = "not_a_player"
+)
+
+// ErrorDesc maps the error to its code and HTTP status.
+type ErrorDesc struct {
+	Err    error
+	Code   ErrorCode
+	Status int
+}
+
+var DomainErrors = []ErrorDesc{
+	{domain.ErrNotFound, CodeNotFound, http.StatusNotFound},
+
+	{domain.ErrInvalidSide, CodeInvalidSide, http.StatusBadRequest},
+	{domain.ErrInvalidWinSide, CodeInvalidWinSide, http.StatusBadRequest},
+
+	{domain.ErrGameNotStarted, CodeGameNotStarted, http.StatusConflict},
+	{domain.ErrGameAlreadyStarted, CodeGameAlreadyStarted, http.StatusConflict},
+	{domain.ErrGameFinished, CodeGameFinished, http.StatusConflict},
+
+	{domain.ErrGameIsNil, CodeInternal, http.StatusInternalServerError},
+
+	{domain.ErrInvalidState, CodeInvalidState, http.StatusBadRequest},
+
+	{domain.ErrPlaceAlreadyTaken, CodePlaceAlreadyTaken, http.StatusConflict},
+	{domain.ErrMoveOutOfBoard, CodeMoveOutOfBoard, http.StatusUnprocessableEntity},
+	{domain.ErrInvalidMoveInGameID, CodeInvalidMoveID, http.StatusConflict},
+	{domain.ErrInvalidSideTurn, CodeNotYourTurn, http.StatusConflict},
+
+	{domain.ErrInvalidPly, CodeInvalidPly, http.StatusBadRequest},
+
+	{domain.ErrUnsupportedMode, CodeUnsupportedMode, http.StatusUnprocessableEntity},
+	{domain.ErrUnsupportedConfig, CodeUnsupportedConfig, http.StatusUnprocessableEntity},
+	{domain.ErrMovesAfterFinish, CodeMovesAfterFinish, http.StatusUnprocessableEntity},
+	{domain.ErrGameResultMismatch, CodeGameResultMismatch, http.StatusUnprocessableEntity},
+	{domain.ErrMissingPlayers, CodeMissingPlayers, http.StatusUnprocessableEntity},
+
+	{domain.ErrNoMoveToTakeback, CodeNoMoveToTakeback, http.StatusConflict},
+	{domain.ErrLastMoveIsNotYours, CodeLastMoveIsNotYours, http.StatusConflict},
+	{domain.ErrTakebackAlreadyProposed, CodeTakebackAlreadyProposed, http.StatusConflict},
+	{domain.ErrTakebackNotProposed, CodeTakebackNotProposed, http.StatusConflict},
+	{domain.ErrCantAnswerOwnTakeback, CodeCantAnswerOwnTakeback, http.StatusConflict},
+	{domain.ErrDrawAlreadyOffered, CodeDrawAlreadyOffered, http.StatusConflict},
+	{domain.ErrDrawNotOffered, CodeDrawNotOffered, http.StatusConflict},
+	{domain.ErrCantAnswerOwnDrawOffer, CodeCantAnswerOwnDrawOffer, http.StatusConflict},
+	{domain.ErrPlayerIsNotInGame, CodePlayerIsNotInGame, http.StatusForbidden},
+
+	{domain.ErrAlreadyJoined, CodeAlreadyJoined, http.StatusConflict},
+	{domain.ErrAllPlacesAlreadyTaken, CodeAllPlacesAlreadyTaken, http.StatusConflict},
+	{domain.ErrNotEnoughPlayers, CodeNotEnoughPlayers, http.StatusConflict},
+
+	{domain.ErrNegativePlayerFiguresLimit, CodeInvalidConfig, http.StatusUnprocessableEntity},
+	{domain.ErrNegativeOrZeroedWinLineLength, CodeInvalidConfig, http.StatusUnprocessableEntity},
+	{domain.ErrNegativeOrZeroedBoardWidth, CodeInvalidConfig, http.StatusUnprocessableEntity},
+	{domain.ErrNegativeOrZeroedBoardHeight, CodeInvalidConfig, http.StatusUnprocessableEntity},
+
+	{domain.ErrInvalidSeriesBestOf, CodeInvalidSeriesBestOf, http.StatusUnprocessableEntity},
+	{domain.ErrSeriesFinished, CodeSeriesFinished, http.StatusConflict},
+
+	{domain.ErrInvalidUsername, CodeInvalidUsername, http.StatusUnprocessableEntity},
+	{domain.ErrWeakPassword, CodeWeakPassword, http.StatusUnprocessableEntity},
+	{domain.ErrInvalidDisplayName, CodeInvalidDisplayName, http.StatusUnprocessableEntity},
+	{domain.ErrUsernameTaken, CodeUsernameTaken, http.StatusConflict},
+	{domain.ErrInvalidCredentials, CodeInvalidCredentials, http.StatusUnauthorized},
+	{domain.ErrAlreadyRegistered, CodeAlreadyRegistered, http.StatusConflict},
+
+	{domain.ErrInvalidLeaderboard, CodeInvalidLeaderboard, http.StatusBadRequest},
+	{domain.ErrMissingRatingPool, CodeMissingRatingPool, http.StatusBadRequest},
+}
+
+var AuthErrors = []ErrorDesc{
+	{ErrMissingToken, CodeMissingToken, http.StatusUnauthorized},
+	{ErrInvalidToken, CodeInvalidToken, http.StatusUnauthorized},
+}
+
+// ErrRateLimited is returned to the clients exceeding the rate limits.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+// ErrShuttingDown is returned to the new long-lived connections while the server is shutting down.
+var ErrShuttingDown = errors.New("server is shutting down")
+
+var RequestErrors = []ErrorDesc{
+	{ErrInvalidPagination, CodeInvalidPagination, http.StatusBadRequest},
+	{ErrOriginNotAllowed, CodeOriginNotAllowed, http.StatusForbidden},
+	{ErrRateLimited, CodeRateLimited, http.StatusTooManyRequests},
+	{ErrShuttingDown, CodeShuttingDown, http.StatusServiceUnavailable},
+}
+
+// codes of the errors that aren't in the catalogue
+var statusCodes = map[int]ErrorCode{
+	http.StatusBadRequest:          CodeBadRequest,
+	http.StatusUnauthorized:        CodeUnauthorized,
+	http.StatusForbidden:           CodeForbidden,
+	http.StatusNotFound:            CodeNotFound,
+	http.StatusConflict:            CodeConflict,
+	http.StatusUnprocessableEntity: CodeUnprocessable,
+	http.StatusTooManyRequests:     CodeRateLimited,
+	http.StatusNotImplemented:      CodeNotImplemented,
+}
+
+const DefaultLanguage = "en"
+
+// ErrConverter converts errors to the codes, HTTP statuses and messages
+// in the language of the client.
+type ErrConverter struct {
+	descs []ErrorDesc
+	// messages by language and code
+	messages map[string]map[ErrorCode]string
+}
+
+// NewErrConverter creates the converter of the domain, auth and request errors and the given ones.
+func NewErrConverter(descs ...ErrorDesc) *ErrConverter {
+	return &ErrConverter{
+		descs:    slices.Concat(DomainErrors, AuthErrors, RequestErrors, descs),
+		messages: Messages,
+	}
+}
+
+// Convert returns the description of the first matching error of the catalogue.
+// Unknown errors get the code of the fallback status.
+func (c *ErrConverter) Convert(err error, status int) (ErrorDesc, bool) {
+	for _, desc := range c.descs {
+		if errors.Is(err, desc.Err) {
+			return desc, true
+		}
+	}
+
+	code, ok := statusCodes[status]
+	if !ok {
+		code = CodeInternal
+		status = http.StatusInternalServerError
+	}
+	return ErrorDesc{Err: err, Code: code, Status: status}, false
+}
+
+// Codes returns all the codes the converter can return.
+func (c *ErrConverter) Codes() []ErrorCode {
+	codes := []ErrorCode{CodeInternal}
+	for _, code := range statusCodes {
+		codes = append(codes, code)
+	}
+	for _, desc := range c.descs {
+		codes = append(codes, desc.Code)
+	}
+
+	slices.Sort(codes)
+	return slices.Compact(codes)
+}
+
+// Message returns the message of the code in the first supported language
+// of Accept-Language header value.
+func (c *ErrConverter) Message(code ErrorCode, acceptLanguage string) string {
+	msgs := c.messages[ParseLanguage(acceptLanguage, c.messages)]
+	if msg, ok := msgs[code]; ok {
+		return msg
+	}
+	return c.messages[DefaultLanguage][code]
+}
+
+// HTTPError returns the error body and the HTTP status of the error.
+// Known errors get the localized message, unknown client errors keep their
+// own text and unknown server errors are hidden.
+func (c *ErrConverter) HTTPError(err error, status int, acceptLanguage string) (*HTTPError, int) {
+	desc, known := c.Convert(err, status)
+
+	msg := c.Message(desc.Code, acceptLanguage)
+	if !known && desc.Status < http.StatusInternalServerError {
+		msg = err.Error()
+	}
+
+	return &HTTPError{Error: msg, Code: desc.Code}, desc.Status
+}
+
+// ParseLanguage returns the most preferred language of Accept-Language
+// header value that has the messages, or DefaultLanguage.
+// Languages with q=0 are not acceptable, the ones with invalid q are skipped.
+func ParseLanguage[V any](acceptLanguage string, supported map[string]V) string {
+	type tag struct {
+		lang string
+		q    float64
+	}
+
+	tags := make([]tag, 0)
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		lang, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		lang, _, _ = strings.Cut(strings.ToLower(strings.TrimSpace(lang)), "-")
+
+		q, ok := parseQuality(params)
+		if !ok || q == 0 {
+			continue
+		}
+		tags = append(tags, tag{lang: lang, q: q})
+	}
+
+	slices.SortStableFunc(tags, func(a, b tag) int {
+		return cmp.Compare(b.q, a.q)
+	})
+
+	for _, t := range tags {
+		if _, ok := supported[t.lang]; ok {
+			return t.lang
+		}
+	}
+	return DefaultLanguage
+}
+
+// parseQuality returns q of the language tag parameters, it's 1 if not set.
+func parseQuality(params string) (float64, bool) {
+	for _, param := range strings.Split(params, ";") {
+		v, ok := strings.CutPrefix(strings.TrimSpace(param), "q=")
+		if !ok {
+			continue
+		}
+
+		q, err := strconv.ParseFloat(v, 64)
+		// NaN is rejected too
+		if err != nil || !(q >= 0 && q <= 1) {
+			return 0, false
+		}
+		return q, true
+	}
+	return 1, true
+}
@@ -0,0 +1,105 @@
+package restapi
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+)
+
+func TestErrConverter_HTTPError(t *testing.T) {
+	errMissing := errors.New("missing header")
+	c := NewErrConverter(ErrorDesc{Err: errMissing, Code: CodeMissingToken, Status: http.StatusUnauthorized})
+
+	tests := []struct {
+		name     string
+		err      error
+		status   int
+		lang     string
+		expected *HTTPError
+		code     int
+	}{
+		{
+			name:     "wrapped domain error",
+			err:      fmt.Errorf("move_id(3) ingame_id(2): %w", domain.ErrInvalidSideTurn),
+			status:   http.StatusBadRequest,
+			expected: &HTTPError{Error: "It's not your turn", Code: CodeNotYourTurn},
+			code:     http.StatusConflict,
+		},
+		{
+			name:     "localized",
+			err:      domain.ErrNotFound,
+			status:   http.StatusInternalServerError,
+			lang:     "de-DE, ru;q=0.9, en;q=0.8",
+			expected: &HTTPError{Error: "Не найдено", Code: CodeNotFound},
+			code:     http.StatusNotFound,
+		},
+		{
+			name:     "additional error",
+			err:      errMissing,
+			status:   http.StatusBadRequest,
+			expected: &HTTPError{Error: "Sign in to continue", Code: CodeMissingToken},
+			code:     http.StatusUnauthorized,
+		},
+		{
+			name:     "unknown client error keeps its text",
+			err:      errors.New("invalid UUID length: 3"),
+			status:   http.StatusBadRequest,
+			expected: &HTTPError{Error: "invalid UUID length: 3", Code: CodeBadRequest},
+			code:     http.StatusBadRequest,
+		},
+		{
+			name:     "unknown server error is hidden",
+			err:      errors.New("connection refused"),
+			status:   http.StatusInternalServerError,
+			lang:     "ru",
+			expected: &HTTPError{Error: "Внутренняя ошибка сервера", Code: CodeInternal},
+			code:     http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpErr, code := c.HTTPError(tt.err, tt.status, tt.lang)
+			assert.Equal(t, tt.expected, httpErr)
+			assert.Equal(t, tt.code, code)
+		})
+	}
+}
+
+func TestErrConverter_MessagesOfAllCodes(t *testing.T) {
+	c := NewErrConverter(
+		ErrorDesc{Code: CodeWrongMessageType}, ErrorDesc{Code: CodeUnsupportedProtocolVersion},
+		ErrorDesc{Code: CodeInvalidAction}, ErrorDesc{Code: CodeInvalidPagination},
+		ErrorDesc{Code: CodeNotAPlayer},
+	)
+
+	for lang, msgs := range Messages {
+		for _, code := range c.Codes() {
+			assert.NotEmpty(t, msgs[code], "%v message of %v", lang, code)
+		}
+	}
+}
+
+func TestParseLanguage(t *testing.T) {
+	tests := map[string]string{
+		"":                           DefaultLanguage,
+		"ru":                         "ru",
+		"ru-RU,ru;q=0.9":             "ru",
+		"fr, en;q=0.5, ru;q=0.7":     "ru",
+		"de":                         DefaultLanguage,
+		"en, ru;q=1.0":               "en",
+		"en;q=0.5, ru;q=.6":          "ru",
+		"de, ru;q=0":                 DefaultLanguage,
+		"ru;q=0":                     DefaultLanguage,
+		"ru;q=abc, en;q=0.5":         "en",
+		"ru;q=NaN, en;q=0.5":         "en",
+		"ru;level=1;q=0.9, en;q=0.8": "ru",
+	}
+
+	for header, expected := range tests {
+		assert.Equal(t, expected, ParseLanguage(header, Messages), header)
+	}
+}
@@ -2,7 +2,9 @@ package gamesrest
 
 import (
 	"dataxo-backend-game-ms/internal/ports/restapi"
+	"dataxo-backend-game-ms/pkg/eventhub"
 	"dataxo-backend-game-ms/pkg/slogdiscard"
+	"github.com/google/uuid"
 	"github.com/olahol/melody"
 	"log/slog"
 	"net"
@@ -13,14 +15,84 @@ type Handler struct {
 	log         *slog.Logger
 	gameUC      GameUsecase
 	responder   restapi.Responder
-	wsResponder restapi.WsResponder
+	wsResponder restapi.WsCodec
+	// WebSocket codecs by subprotocol, wsResponder is used without subprotocol
+	wsCodecs map[string]restapi.WsCodec
+	// converts errors of WebSocket messages to the codes
+	errConverter *restapi.ErrConverter
 
 	wsHandler *melody.Melody
+	wsRoutes  map[WsRoute]WsHandlerFunc
+	// game events for SSE subscribers and redelivery
+	events *eventhub.Hub[uuid.UUID]
+	// held by the changes of the game, see gameLocks
+	gameLocks *gameLocks
+
+	rateLimits RateLimits
+	limiters   *rateLimiters
+
+	wsConfig WsConfig
+	presence *presence
+	shutdown *shutdown
+	// nil if the metrics aren't collected
+	metrics Metrics
+}
+
+const gameEventsBufferSize = 256
+
+type Opt func(h *Handler)
+
+// WithErrConverter replaces the converter of WebSocket errors,
+// it should know the handler Errors.
+func WithErrConverter(errConverter *restapi.ErrConverter) Opt {
+	return func(h *Handler) {
+		h.errConverter = errConverter
+	}
+}
+
+// WithWsCodec adds the codec of WebSocket messages negotiated by Sec-WebSocket-Protocol.
+func WithWsCodec(subprotocol string, codec restapi.WsCodec) Opt {
+	return func(h *Handler) {
+		h.wsCodecs[subprotocol] = codec
+	}
+}
+
+// WithCheckOrigin sets the check of Origin of the WebSocket handshakes, all origins are allowed by default.
+// The rejected handshakes are logged.
+func WithCheckOrigin(checkOrigin func(r *http.Request) bool) Opt {
+	return func(h *Handler) {
+		h.wsHandler.Upgrader.CheckOrigin = func(r *http.Request) bool {
+			if checkOrigin(r) {
+				return true
+			}
+
+			h.log.Warn("WebSocket origin is not allowed",
+				slog.String("origin", r.Header.Get("Origin")),
+				slog.String("remote_addr", h.GetRemoteAddr(r)),
+			)
+			return false
+		}
+	}
 }
 
-func New(log *slog.Logger, gameUC GameUsecase, responder restapi.Responder, wsResponder restapi.WsResponder) *Handler {
+func New(log *slog.Logger, gameUC GameUsecase, responder restapi.Responder, wsResponder restapi.WsCodec, opts ...Opt) *Handler {
 	log = slogdiscard.LoggerIfNil(log)
-	return &Handler{log: log, gameUC: gameUC, responder: responder, wsResponder: wsResponder, wsHandler: melody.New()}
+	h := &Handler{log: log, gameUC: gameUC, responder: responder, wsResponder: wsResponder,
+		wsCodecs:     map[string]restapi.WsCodec{JsonSubprotocol: wsResponder},
+		errConverter: restapi.NewErrConverter(Errors...),
+		wsHandler:    melody.New(), events: eventhub.New[uuid.UUID](gameEventsBufferSize),
+		rateLimits: DefaultRateLimits(), wsConfig: DefaultWsConfig(), presence: newPresence(),
+		shutdown: newShutdown(), gameLocks: newGameLocks()}
+
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	h.limiters = newRateLimiters(h.rateLimits)
+	h.applyWsConfig()
+
+	h.setupWsRoutes()
+	return h
 }
 
 func (h *Handler) GetRemoteAddr(r *http.Request) string {
@@ -4,5 +4,14 @@ import "github.com/go-chi/chi/v5"
 
 func (h *Handler) SetupRoutes(r chi.Router) {
 	r.Post("/api/v1/games/modes/with-friend", h.CreateWithFriend())
-	r.Handle("/api/v1/games/{game_id}/{client_id}", h.WsMux())
+	r.Get("/api/v1/games", h.ListGames())
+	r.Get("/api/v1/games/{game_id}", h.GetGame())
+	r.Get("/api/v1/series/{series_id}", h.GetSeries())
+	r.Get("/api/v1/games/{game_id}/history", h.GetHistory())
+	r.Post("/api/v1/games/{game_id}/moves", h.MakeMove())
+	r.Get("/api/v1/games/{game_id}/events", h.GameEvents())
+	r.Get("/api/v1/games/{game_id}/board", h.GetBoard())
+	r.Get("/api/v1/games/{game_id}/export", h.ExportGame())
+	r.Post("/api/v1/games/import", h.ImportGame())
+	r.Get("/api/v1/games/{game_id}/ws", h.WsMux())
 }
@@ -0,0 +1,57 @@
+package gamesrest
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type BoardResp struct {
+	GameID          uuid.UUID     `json:"game_id"`
+	Ply             int           `json:"ply"`
+	NextSide        domain.Side   `json:"next_side"`
+	Cells           []domain.Move `json:"cells"`
+	NextToDisappear *domain.Move  `json:"next_to_disappear"`
+}
+
+func (r *BoardResp) FromDomain(gameID uuid.UUID, snapshot domain.BoardSnapshot) {
+	r.GameID = gameID
+	r.Ply = snapshot.Ply
+	r.NextSide = snapshot.NextSide
+	r.Cells = snapshot.Cells
+	r.NextToDisappear = snapshot.NextToDisappear
+}
+
+func (h *Handler) GetBoard() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		gameID, err := uuid.Parse(chi.URLParam(r, "game_id"))
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		ply := -1
+		if plyParam := r.URL.Query().Get("ply"); plyParam != "" {
+			ply, err = strconv.Atoi(plyParam)
+			if err != nil || ply < 0 {
+				h.responder.RespondError(w, r, http.StatusBadRequest, domain.ErrInvalidPly)
+				return
+			}
+		}
+
+		snapshot, err := h.gameUC.GetBoard(r.Context(), gameID, ply)
+		if err != nil {
+			log.Error("uc get board", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := &BoardResp{}
+		resp.FromDomain(gameID, snapshot)
+		h.responder.Respond(w, http.StatusOK, resp)
+	}
+}
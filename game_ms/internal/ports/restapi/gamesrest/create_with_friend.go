@@ -2,6 +2,7 @@ package gamesrest
 
 import (
 	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
 	"encoding/json"
 	"log/slog"
 	"net/http"
@@ -9,15 +10,21 @@ import (
 
 type ModeParams struct {
 	MySide int `json:"my_side"`
+	BestOf int `json:"best_of"`
+	// the game result changes the ratings of the players
+	Rated bool `json:"rated"`
 }
 
 type CreateWithFriendReq struct {
 	ModeParams ModeParams `json:"mode_params"`
-	ClientID   string     `json:"client_id"`
 }
 
 func (r *CreateWithFriendReq) ToDomain() domain.ModeParams {
-	return domain.ModeParams{MySide: domain.SideRequest(r.ModeParams.MySide)}
+	return domain.ModeParams{
+		MySide: domain.SideRequest(r.ModeParams.MySide),
+		BestOf: r.ModeParams.BestOf,
+		Rated:  r.ModeParams.Rated,
+	}
 }
 
 type CreateWithFriendResp struct {
@@ -31,20 +38,30 @@ func (r *CreateWithFriendResp) FromDomain(game *domain.Game) {
 func (h *Handler) CreateWithFriend() http.HandlerFunc {
 	log := h.log
 	return func(w http.ResponseWriter, r *http.Request) {
+		player, err := restapi.PlayerIDFromContext(r.Context())
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusUnauthorized, err)
+			return
+		}
+
+		if !h.allowGameCreation(r) {
+			h.responder.RespondError(w, r, http.StatusTooManyRequests, restapi.ErrRateLimited)
+			return
+		}
+
 		req := &CreateWithFriendReq{}
 		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
-			h.responder.RespondError(w, http.StatusBadRequest, err)
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
 			return
 		}
 
-		player := domain.PlayerID{ClientID: req.ClientID}
 		modeParams := req.ToDomain()
 		h.log.Debug("create game", slog.Any("playerID", player))
 
 		g, err := h.gameUC.CreateGame(r.Context(), player, domain.ModeWithFriend, modeParams)
 		if err != nil {
 			log.Error("uc create game", slog.Any("error", err))
-			h.responder.RespondError(w, http.StatusInternalServerError, err)
+			h.responder.RespondError(w, r, http.StatusInternalServerError, err)
 			return
 		}
 
@@ -1,21 +1,50 @@
 package gamesrest
 
 import (
+	"dataxo-backend-game-ms/internal/ports/restapi"
 	"errors"
 	"fmt"
+	"net/http"
 )
 
 var (
-	ErrWrongMessageType = errors.New("wrong message type")
+	ErrWrongMessageType           = errors.New("wrong message type")
+	ErrUnsupportedProtocolVersion = errors.New("unsupported protocol version")
+	ErrFeatureNotNegotiated       = errors.New("feature is not negotiated")
 
 	ErrGameNotStarted     = errors.New("game not started")
 	ErrGameAlreadyStarted = errors.New("game already started")
 
-	ErrInvalidPresenceAction = errors.New("invalid presence action")
+	ErrInvalidPresenceAction        = errors.New("invalid presence action")
+	ErrPresenceActionNotImplemented = errors.New("presence action is not implemented yet")
 
 	ErrInvalidReadinessAction = errors.New("invalid readiness action")
+
+	ErrInvalidTakebackAction = errors.New("invalid takeback action")
+
+	ErrNotAPlayer = errors.New("client is not a player of the game")
 )
 
+// Errors are the codes of the handler errors in addition to restapi.DomainErrors.
+var Errors = []restapi.ErrorDesc{
+	{Err: ErrWrongMessageType, Code: restapi.CodeWrongMessageType, Status: http.StatusBadRequest},
+	{Err: ErrUnsupportedProtocolVersion, Code: restapi.CodeUnsupportedProtocolVersion, Status: http.StatusBadRequest},
+	{Err: ErrFeatureNotNegotiated, Code: restapi.CodeFeatureNotNegotiated, Status: http.StatusBadRequest},
+
+	{Err: ErrGameNotStarted, Code: restapi.CodeGameNotStarted, Status: http.StatusConflict},
+	{Err: ErrGameAlreadyStarted, Code: restapi.CodeGameAlreadyStarted, Status: http.StatusConflict},
+
+	{Err: ErrInvalidPresenceAction, Code: restapi.CodeInvalidAction, Status: http.StatusBadRequest},
+	{Err: ErrPresenceActionNotImplemented, Code: restapi.CodeNotImplemented, Status: http.StatusNotImplemented},
+	{Err: ErrInvalidReadinessAction, Code: restapi.CodeInvalidAction, Status: http.StatusBadRequest},
+	{Err: ErrInvalidTakebackAction, Code: restapi.CodeInvalidAction, Status: http.StatusBadRequest},
+
+	{Err: ErrNotAPlayer, Code: restapi.CodeNotAPlayer, Status: http.StatusForbidden},
+
+	{Err: ErrCantGetGameIDFromSession, Code: restapi.CodeInternal, Status: http.StatusInternalServerError},
+	{Err: ErrCantConvertGameIDValueToUUID, Code: restapi.CodeInternal, Status: http.StatusInternalServerError},
+}
+
 type PresenceActionError struct {
 	Err    error
 	Action string
@@ -41,3 +70,42 @@ func (e *ReadinessError) Error() string {
 func (e *ReadinessError) Unwrap() error {
 	return e.Err
 }
+
+type TakebackActionError struct {
+	Err    error
+	Action string
+}
+
+func (e *TakebackActionError) Error() string {
+	return fmt.Sprintf("action(%v): %v", e.Action, e.Err)
+}
+
+func (e *TakebackActionError) Unwrap() error {
+	return e.Err
+}
+
+type ProtocolVersionError struct {
+	Err     error
+	Version int
+}
+
+func (e *ProtocolVersionError) Error() string {
+	return fmt.Sprintf("version(%v): %v", e.Version, e.Err)
+}
+
+func (e *ProtocolVersionError) Unwrap() error {
+	return e.Err
+}
+
+type FeatureError struct {
+	Err     error
+	Feature string
+}
+
+func (e *FeatureError) Error() string {
+	return fmt.Sprintf("feature(%v): %v", e.Feature, e.Err)
+}
+
+func (e *FeatureError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,49 @@
+package gamesrest
+
+import (
+	"github.com/google/uuid"
+	"sync"
+)
+
+// gameLocks serialize the changes of the game with their broadcasts and the reads
+// of the game state with the sequence number, other games don't wait for them.
+type gameLocks struct {
+	locks map[uuid.UUID]*gameLock
+	mu    sync.Mutex
+}
+
+type gameLock struct {
+	mu sync.Mutex
+	// number of the holders and waiters, the lock is deleted when it's zero
+	refs int
+}
+
+func newGameLocks() *gameLocks {
+	return &gameLocks{locks: make(map[uuid.UUID]*gameLock)}
+}
+
+// lock locks the game and returns the function that unlocks it.
+func (l *gameLocks) lock(gameID uuid.UUID) func() {
+	l.mu.Lock()
+	gl, ok := l.locks[gameID]
+	if !ok {
+		gl = &gameLock{}
+		l.locks[gameID] = gl
+	}
+	gl.refs++
+	l.mu.Unlock()
+
+	gl.mu.Lock()
+
+	return func() {
+		gl.mu.Unlock()
+
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		gl.refs--
+		if gl.refs == 0 {
+			delete(l.locks, gameID)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package gamesrest
+
+import (
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestGameLocks(t *testing.T) {
+	l := newGameLocks()
+	game, other := uuid.New(), uuid.New()
+
+	unlock := l.lock(game)
+
+	done := make(chan struct{})
+	go func() {
+		l.lock(other)()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("other game waits for the locked one")
+	}
+
+	locked := make(chan struct{})
+	go func() {
+		unlockAgain := l.lock(game)
+		close(locked)
+		unlockAgain()
+	}()
+	select {
+	case <-locked:
+		t.Fatal("game is locked twice")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	unlock()
+	<-locked
+
+	assert.Eventually(t, func() bool {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return len(l.locks) == 0
+	}, time.Second, 10*time.Millisecond, "unused locks are deleted")
+}
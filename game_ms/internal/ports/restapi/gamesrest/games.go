@@ -0,0 +1,86 @@
+package gamesrest
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	defaultGamesLimit = 20
+	maxGamesLimit     = 100
+)
+
+type ListGamesResp struct {
+	Games  []GameResp `json:"games"`
+	Total  int        `json:"total"`
+	Limit  int        `json:"limit"`
+	Offset int        `json:"offset"`
+}
+
+func (h *Handler) GetGame() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		gameID, err := uuid.Parse(chi.URLParam(r, "game_id"))
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		resp, err := h.GetGameResp(r.Context(), gameID)
+		if err != nil {
+			log.Error("get game", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		h.responder.Respond(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) ListGames() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		filter := domain.GameFilter{Mode: query.Get("mode")}
+
+		if stateParam := query.Get("state"); stateParam != "" {
+			state, err := domain.ParseState(stateParam)
+			if err != nil {
+				h.responder.RespondError(w, r, http.StatusBadRequest, err)
+				return
+			}
+			filter.State = &state
+		}
+
+		var err error
+		filter.Limit, filter.Offset, err = restapi.ParsePagination(r, defaultGamesLimit, maxGamesLimit)
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		games, total, err := h.gameUC.ListGames(r.Context(), filter)
+		if err != nil {
+			log.Error("uc list games", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := &ListGamesResp{
+			Games:  make([]GameResp, len(games)),
+			Total:  total,
+			Limit:  filter.Limit,
+			Offset: filter.Offset,
+		}
+		for i := range games {
+			resp.Games[i].FromDomain(games[i])
+		}
+
+		h.responder.Respond(w, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,142 @@
+package gamesrest
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type listGamesUC struct {
+	GameUsecase
+	games []*domain.Game
+	err   error
+	// the filter of the last call
+	filter domain.GameFilter
+}
+
+func (uc *listGamesUC) GetGame(ctx context.Context, gameID uuid.UUID) (*domain.Game, error) {
+	if uc.err != nil {
+		return nil, uc.err
+	}
+	for _, g := range uc.games {
+		if g.ID == gameID {
+			return g, nil
+		}
+	}
+	return nil, &domain.GameErrorWithID{Err: domain.ErrNotFound, ID: gameID}
+}
+
+func (uc *listGamesUC) ListGames(ctx context.Context, filter domain.GameFilter) ([]*domain.Game, int, error) {
+	uc.filter = filter
+	return uc.games, 42, uc.err
+}
+
+func serveGames(uc GameUsecase, target string) *httptest.ResponseRecorder {
+	responder := restapi.NewJsonResponder(nil, restapi.NewErrConverter(Errors...))
+	router := chi.NewRouter()
+	New(nil, uc, responder, responder).SetupRoutes(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) restapi.ErrorCode {
+	httpErr := &restapi.HTTPError{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), httpErr))
+	return httpErr.Code
+}
+
+func TestHandler_ListGames(t *testing.T) {
+	games := []*domain.Game{
+		{ID: uuid.New(), Mode: domain.ModeWithFriend, State: domain.Started},
+		{ID: uuid.New(), Mode: domain.ModeWithFriend, State: domain.Started},
+	}
+	uc := &listGamesUC{games: games}
+
+	w := serveGames(uc, "/api/v1/games?state=started&mode=with-friend&limit=2&offset=4")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	started := domain.Started
+	assert.Equal(t, domain.GameFilter{Mode: domain.ModeWithFriend, State: &started, Limit: 2, Offset: 4}, uc.filter)
+
+	resp := &ListGamesResp{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+	assert.Equal(t, 42, resp.Total)
+	assert.Equal(t, 2, resp.Limit)
+	assert.Equal(t, 4, resp.Offset)
+	require.Len(t, resp.Games, 2)
+	assert.Equal(t, games[1].ID, resp.Games[1].GameID)
+	assert.Equal(t, "started", resp.Games[1].State)
+
+	w = serveGames(uc, "/api/v1/games")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, domain.GameFilter{Limit: defaultGamesLimit}, uc.filter, "no filters")
+}
+
+func TestHandler_ListGamesErrors(t *testing.T) {
+	tcases := []struct {
+		Name   string
+		Target string
+		Err    error
+		Status int
+		Code   restapi.ErrorCode
+	}{
+		{Name: "unknown state", Target: "/api/v1/games?state=paused", Status: http.StatusBadRequest, Code: restapi.CodeInvalidState},
+		{Name: "limit over max", Target: "/api/v1/games?limit=101", Status: http.StatusBadRequest, Code: restapi.CodeInvalidPagination},
+		{Name: "negative offset", Target: "/api/v1/games?offset=-1", Status: http.StatusBadRequest, Code: restapi.CodeInvalidPagination},
+		{Name: "store error", Target: "/api/v1/games", Err: errors.New("store is down"),
+			Status: http.StatusInternalServerError, Code: restapi.CodeInternal},
+	}
+
+	for _, tc := range tcases {
+		w := serveGames(&listGamesUC{err: tc.Err}, tc.Target)
+		require.Equal(t, tc.Status, w.Code, tc.Name)
+		assert.Equal(t, tc.Code, errorCode(t, w), tc.Name)
+	}
+}
+
+func TestHandler_GetGame(t *testing.T) {
+	g := &domain.Game{ID: uuid.New(), Mode: domain.ModeWithFriend, State: domain.Finished, Winner: domain.OWin,
+		XPlayer: &domain.Player{ID: domain.PlayerID{ClientID: "alice"}}}
+
+	w := serveGames(&listGamesUC{games: []*domain.Game{g}}, "/api/v1/games/"+g.ID.String())
+	require.Equal(t, http.StatusOK, w.Code)
+
+	resp := &GameResp{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+	assert.Equal(t, g.ID, resp.GameID)
+	assert.Equal(t, "finished", resp.State)
+	assert.Equal(t, domain.OWin, resp.Winner)
+	require.NotNil(t, resp.Players.X)
+	assert.Equal(t, "alice", resp.Players.X.ClientID)
+	assert.Nil(t, resp.Players.O)
+
+	tcases := []struct {
+		Name   string
+		Target string
+		Err    error
+		Status int
+		Code   restapi.ErrorCode
+	}{
+		{Name: "invalid id", Target: "/api/v1/games/42", Status: http.StatusBadRequest, Code: restapi.CodeBadRequest},
+		{Name: "not found", Target: "/api/v1/games/" + uuid.NewString(), Status: http.StatusNotFound, Code: restapi.CodeNotFound},
+		{Name: "store error", Target: "/api/v1/games/" + uuid.NewString(), Err: errors.New("store is down"),
+			Status: http.StatusInternalServerError, Code: restapi.CodeInternal},
+	}
+
+	for _, tc := range tcases {
+		w := serveGames(&listGamesUC{err: tc.Err}, tc.Target)
+		require.Equal(t, tc.Status, w.Code, tc.Name)
+		assert.Equal(t, tc.Code, errorCode(t, w), tc.Name)
+	}
+}
@@ -9,8 +9,19 @@ import (
 type GameUsecase interface {
 	CreateGame(ctx context.Context, playerID domain.PlayerID, mode string, params domain.ModeParams) (*domain.Game, error)
 	GetGame(ctx context.Context, gameID uuid.UUID) (*domain.Game, error)
+	ListGames(ctx context.Context, filter domain.GameFilter) ([]*domain.Game, int, error)
 	JoinGame(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID) (domain.JoinGameResult, error)
 	StartGame(ctx context.Context, gameID uuid.UUID) error
 	MakeMove(ctx context.Context, gameID uuid.UUID, move domain.Move) (domain.MakeMoveResult, error)
 	GetSide(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID) (domain.Side, error)
+	ProposeTakeback(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID) (domain.Side, error)
+	AnswerTakeback(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID, accept bool) (domain.MakeMoveResult, error)
+	OfferDraw(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID) (domain.Side, error)
+	AnswerDraw(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID, accept bool) (domain.MakeMoveResult, error)
+	Resign(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID) (domain.MakeMoveResult, error)
+	GetHistory(ctx context.Context, gameID uuid.UUID) ([]domain.HistoryEvent, error)
+	GetBoard(ctx context.Context, gameID uuid.UUID, ply int) (domain.BoardSnapshot, error)
+	ExportGame(ctx context.Context, gameID uuid.UUID) (string, error)
+	ImportGame(ctx context.Context, importer domain.PlayerID, text string) (*domain.Game, error)
+	GetSeries(ctx context.Context, seriesID uuid.UUID) (*domain.Series, error)
 }
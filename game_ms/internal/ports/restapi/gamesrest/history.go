@@ -0,0 +1,83 @@
+package gamesrest
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type HistoryEvent struct {
+	Kind       string    `json:"kind"`
+	MoveNumber int       `json:"move_number"`
+	Actor      string    `json:"actor"`
+	Takeback   bool      `json:"takeback"`
+	Time       time.Time `json:"time"`
+
+	// the move fields are set for the move kind only
+	Type      string      `json:"type,omitempty"`
+	MoveID    int         `json:"move_id"`
+	X         int         `json:"x"`
+	Y         int         `json:"y"`
+	Side      domain.Side `json:"side"`
+	TimesUsed int         `json:"times_used"`
+}
+
+func (e *HistoryEvent) FromDomain(event domain.HistoryEvent) {
+	move := event.Move
+
+	e.Kind = event.Kind.String()
+	e.MoveNumber = event.MoveNumber
+	e.Actor = event.Actor.ClientID
+	e.Takeback = event.Takeback
+	e.Time = event.Time
+
+	if event.Kind != domain.MoveHistoryEvent {
+		return
+	}
+
+	e.Type = MoveEventTypeFromDomain(event.Type)
+	e.MoveID = move.InGameID
+	e.X = move.X
+	e.Y = move.Y
+	e.Side = move.Side
+	e.TimesUsed = move.TimesUsed
+}
+
+type HistoryResp struct {
+	GameID uuid.UUID      `json:"game_id"`
+	Events []HistoryEvent `json:"events"`
+}
+
+func (r *HistoryResp) FromDomain(gameID uuid.UUID, history []domain.HistoryEvent) {
+	r.GameID = gameID
+	r.Events = make([]HistoryEvent, len(history))
+
+	for i := range history {
+		r.Events[i].FromDomain(history[i])
+	}
+}
+
+func (h *Handler) GetHistory() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		gameID, err := uuid.Parse(chi.URLParam(r, "game_id"))
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		history, err := h.gameUC.GetHistory(r.Context(), gameID)
+		if err != nil {
+			log.Error("uc get history", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		resp := &HistoryResp{}
+		resp.FromDomain(gameID, history)
+		h.responder.Respond(w, http.StatusOK, resp)
+	}
+}
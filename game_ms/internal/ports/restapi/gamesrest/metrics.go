@@ -0,0 +1,15 @@
+package gamesrest
+
+// Metrics records the WebSocket activity.
+type Metrics interface {
+	WsSessionOpened()
+	WsSessionClosed()
+	// ObserveBroadcast gets the number of the sessions the game broadcast was sent to.
+	ObserveBroadcast(sessions int)
+}
+
+func WithMetrics(metrics Metrics) Opt {
+	return func(h *Handler) {
+		h.metrics = metrics
+	}
+}
@@ -0,0 +1,100 @@
+package gamesrest
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+type MakeMoveResp struct {
+	MoveEvents   []MoveEvent    `json:"move_events"`
+	GameFinished bool           `json:"game_finished"`
+	Winner       domain.WinSide `json:"winner"`
+	Reason       string         `json:"reason"`
+	NextGameID   string         `json:"next_game_id,omitempty"`
+	// empty if the game isn't rated
+	RatingChanges []RatingChangeResp `json:"rating_changes,omitempty"`
+}
+
+func (r *MakeMoveResp) FromDomain(res domain.MakeMoveResult) {
+	r.MoveEvents = MoveEventsFromDomain(res.Events)
+	r.GameFinished = res.GameFinished
+	r.Winner = res.Winner
+	r.Reason = res.FinishReason.String()
+	if res.NextGameID != uuid.Nil {
+		r.NextGameID = res.NextGameID.String()
+	}
+	r.RatingChanges = RatingChangesFromDomain(res.RatingChanges)
+}
+
+// MakeMove makes a move for the authenticated player
+// with the same semantics as the "game" WebSocket message.
+func (h *Handler) MakeMove() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		gameID, err := uuid.Parse(chi.URLParam(r, "game_id"))
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		playerID, err := restapi.PlayerIDFromContext(ctx)
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusUnauthorized, err)
+			return
+		}
+
+		if !h.allowMessage(r) {
+			h.responder.RespondError(w, r, http.StatusTooManyRequests, restapi.ErrRateLimited)
+			return
+		}
+
+		req := &WsGameReq{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		// the move is broadcast before the next change of the game, as in wsHandleMessage
+		unlock := h.gameLocks.lock(gameID)
+		defer unlock()
+
+		// the errors of the catalogue have their own statuses, the rest are internal
+		side, err := h.gameUC.GetSide(ctx, gameID, playerID)
+		if err != nil && !errors.Is(err, domain.ErrAllPlacesAlreadyTaken) {
+			h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+		// all places error is returned to the clients that aren't in the full game
+		if side == domain.NoneSide {
+			h.responder.RespondError(w, r, http.StatusForbidden, ErrNotAPlayer)
+			return
+		}
+
+		res, err := h.gameUC.MakeMove(ctx, gameID, domain.Move{
+			InGameID: req.MoveID,
+			X:        req.X,
+			Y:        req.Y,
+			Side:     side,
+		})
+		if err != nil {
+			log.Warn("uc make move", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		h.WsBroadcastMove(gameID, middleware.GetReqID(ctx), res)
+
+		resp := &MakeMoveResp{}
+		resp.FromDomain(res)
+		h.responder.Respond(w, http.StatusOK, resp)
+	}
+}
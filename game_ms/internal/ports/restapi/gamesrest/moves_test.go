@@ -0,0 +1,200 @@
+package gamesrest
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"encoding/json"
+	"errors"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// movesGameUC changes the stored game without locking, as mapstore does.
+type movesGameUC struct {
+	spectatorGameUC
+	game *domain.Game
+}
+
+func (uc *movesGameUC) GetSide(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID) (domain.Side, error) {
+	return domain.XSide, nil
+}
+
+func (uc *movesGameUC) MakeMove(ctx context.Context, gameID uuid.UUID, move domain.Move) (domain.MakeMoveResult, error) {
+	move.InGameID = len(uc.game.Moves)
+	// widens the window of the concurrent moves
+	runtime.Gosched()
+	uc.game.Moves = append(uc.game.Moves, move)
+	return domain.MakeMoveResult{Events: []domain.MoveEvent{{Type: domain.PlaceMove, Move: move}}}, nil
+}
+
+func TestHandler_MakeMoveConcurrently(t *testing.T) {
+	const moves = 20
+
+	responder := restapi.NewJsonResponder(nil, nil)
+	uc := &movesGameUC{game: &domain.Game{ID: uuid.New()}}
+	h := New(nil, uc, responder, responder)
+
+	server := newServer(h)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsGameURL(server, uc.game.ID), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < moves; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			resp, err := http.Post(server.URL+"/api/v1/games/"+uc.game.ID.String()+"/moves",
+				"application/json", strings.NewReader(`{"x":1,"y":1}`))
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer resp.Body.Close()
+			assert.Equal(t, http.StatusOK, resp.StatusCode)
+		}()
+	}
+
+	for i := 0; i < moves; i++ {
+		require.NoError(t, conn.WriteJSON(WsMuxReq{Type: "game", RequestID: "ws", Message: WsRawMessage(`{"x":2,"y":2}`)}))
+	}
+	close(start)
+
+	var seqs []int64
+	for successes := 0; successes < moves || len(seqs) < 2*moves; {
+		msg := &struct {
+			Type string `json:"type"`
+			Seq  int64  `json:"seq"`
+		}{}
+		require.NoError(t, conn.ReadJSON(msg))
+
+		switch msg.Type {
+		case SuccessMoveResponseType:
+			successes++
+		case MoveBroadcastType:
+			seqs = append(seqs, msg.Seq)
+		}
+	}
+	wg.Wait()
+
+	assert.Len(t, uc.game.Moves, 2*moves)
+	assert.IsIncreasing(t, seqs, "broadcasts are sent in the order of their sequence numbers")
+}
+
+type moveErrorsGameUC struct {
+	GameUsecase
+	sideErr error
+	side    domain.Side
+	moveErr error
+}
+
+func (uc moveErrorsGameUC) GetSide(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID) (domain.Side, error) {
+	return uc.side, uc.sideErr
+}
+
+func (uc moveErrorsGameUC) MakeMove(ctx context.Context, gameID uuid.UUID, move domain.Move) (domain.MakeMoveResult, error) {
+	return domain.MakeMoveResult{GameFinished: true, Winner: domain.XWin, FinishReason: domain.WinLineFinish}, uc.moveErr
+}
+
+func TestHandler_MakeMove(t *testing.T) {
+	responder := restapi.NewJsonResponder(nil, restapi.NewErrConverter(Errors...))
+
+	tcases := []struct {
+		Name   string
+		UC     moveErrorsGameUC
+		GameID string
+		Body   string
+		NoAuth bool
+		Status int
+		Code   restapi.ErrorCode
+	}{
+		{Name: "ok", UC: moveErrorsGameUC{side: domain.XSide}, Status: http.StatusOK},
+		{Name: "invalid game id", GameID: "42", Status: http.StatusBadRequest, Code: restapi.CodeBadRequest},
+		{Name: "no player", NoAuth: true, Status: http.StatusUnauthorized, Code: restapi.CodeMissingToken},
+		{Name: "invalid body", Body: "{", Status: http.StatusBadRequest, Code: restapi.CodeBadRequest},
+		{
+			Name:   "game not found",
+			UC:     moveErrorsGameUC{sideErr: &domain.GameErrorWithID{Err: domain.ErrNotFound}},
+			Status: http.StatusNotFound, Code: restapi.CodeNotFound,
+		},
+		{
+			Name:   "spectator",
+			UC:     moveErrorsGameUC{side: domain.NoneSide},
+			Status: http.StatusForbidden, Code: restapi.CodeNotAPlayer,
+		},
+		{
+			Name:   "not a player of the full game",
+			UC:     moveErrorsGameUC{sideErr: &domain.AddGamePlayerError{Err: domain.ErrAllPlacesAlreadyTaken}},
+			Status: http.StatusForbidden, Code: restapi.CodeNotAPlayer,
+		},
+		{
+			Name:   "side error",
+			UC:     moveErrorsGameUC{sideErr: errors.New("store is down")},
+			Status: http.StatusInternalServerError, Code: restapi.CodeInternal,
+		},
+		{
+			Name:   "not your turn",
+			UC:     moveErrorsGameUC{side: domain.OSide, moveErr: domain.ErrInvalidSideTurn},
+			Status: http.StatusConflict, Code: restapi.CodeNotYourTurn,
+		},
+		{
+			Name:   "out of board",
+			UC:     moveErrorsGameUC{side: domain.XSide, moveErr: domain.ErrMoveOutOfBoard},
+			Status: http.StatusUnprocessableEntity, Code: restapi.CodeMoveOutOfBoard,
+		},
+		{
+			Name:   "move error",
+			UC:     moveErrorsGameUC{side: domain.XSide, moveErr: errors.New("store is down")},
+			Status: http.StatusInternalServerError, Code: restapi.CodeInternal,
+		},
+	}
+
+	for _, tc := range tcases {
+		h := New(nil, tc.UC, responder, responder)
+		router := chi.NewRouter()
+		h.SetupRoutes(router)
+
+		gameID := tc.GameID
+		if gameID == "" {
+			gameID = uuid.NewString()
+		}
+		body := tc.Body
+		if body == "" {
+			body = `{"move_id":1,"x":1,"y":2}`
+		}
+
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/games/"+gameID+"/moves", strings.NewReader(body))
+		if !tc.NoAuth {
+			r = r.WithContext(restapi.WithPlayerID(r.Context(), domain.PlayerID{ClientID: "alice"}))
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		require.Equal(t, tc.Status, w.Code, tc.Name)
+		if tc.Status == http.StatusOK {
+			resp := &MakeMoveResp{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp), tc.Name)
+			assert.True(t, resp.GameFinished, tc.Name)
+			assert.Equal(t, domain.XWin, resp.Winner, tc.Name)
+			assert.Equal(t, domain.WinLineFinish.String(), resp.Reason, tc.Name)
+			continue
+		}
+
+		httpErr := &restapi.HTTPError{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), httpErr), tc.Name)
+		assert.Equal(t, tc.Code, httpErr.Code, tc.Name)
+	}
+}
@@ -0,0 +1,74 @@
+package gamesrest
+
+import (
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+const maxNotationSize = 64 << 10
+
+type ImportGameResp struct {
+	GameID string `json:"game_id"`
+}
+
+func (h *Handler) ExportGame() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		gameID, err := uuid.Parse(chi.URLParam(r, "game_id"))
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		text, err := h.gameUC.ExportGame(r.Context(), gameID)
+		if err != nil {
+			log.Error("uc export game", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.WriteHeader(http.StatusOK)
+
+		_, err = io.WriteString(w, text)
+		if err != nil {
+			log.Error("export game: write response", slog.Any("error", err))
+		}
+	}
+}
+
+func (h *Handler) ImportGame() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		player, err := restapi.PlayerIDFromContext(r.Context())
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusUnauthorized, err)
+			return
+		}
+
+		if !h.allowGameCreation(r) {
+			h.responder.RespondError(w, r, http.StatusTooManyRequests, restapi.ErrRateLimited)
+			return
+		}
+
+		text, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxNotationSize))
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		g, err := h.gameUC.ImportGame(r.Context(), player, string(text))
+		if err != nil {
+			log.Warn("uc import game", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		h.responder.Respond(w, http.StatusCreated, &ImportGameResp{GameID: g.ID.String()})
+	}
+}
@@ -0,0 +1,112 @@
+package gamesrest
+
+import (
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"dataxo-backend-game-ms/pkg/ratelimit"
+	"github.com/gorilla/websocket"
+	"github.com/olahol/melody"
+	"log/slog"
+	"net/http"
+)
+
+// RateLimits limits the clients by their connections and IP addresses,
+// zero limits aren't applied.
+type RateLimits struct {
+	// messages of one WebSocket connection
+	Messages ratelimit.Limit
+	// messages of all the WebSocket connections and the REST moves of the IP
+	IPMessages ratelimit.Limit
+	// WebSocket connection and SSE subscription attempts of the IP
+	Connections ratelimit.Limit
+	// game creations and imports of the IP
+	GameCreations ratelimit.Limit
+	// rate limited messages of the connection, it's closed when they are exhausted
+	Violations ratelimit.Limit
+}
+
+func DefaultRateLimits() RateLimits {
+	return RateLimits{
+		Messages:      ratelimit.Limit{Rate: 10, Burst: 20},
+		IPMessages:    ratelimit.Limit{Rate: 50, Burst: 100},
+		Connections:   ratelimit.Limit{Rate: 1, Burst: 10},
+		GameCreations: ratelimit.Limit{Rate: 0.2, Burst: 5},
+		Violations:    ratelimit.Limit{Rate: 0.1, Burst: 10},
+	}
+}
+
+// WithRateLimits replaces DefaultRateLimits.
+func WithRateLimits(limits RateLimits) Opt {
+	return func(h *Handler) {
+		h.rateLimits = limits
+	}
+}
+
+type rateLimiters struct {
+	ipMessages    *ratelimit.Limiter[string]
+	connections   *ratelimit.Limiter[string]
+	gameCreations *ratelimit.Limiter[string]
+}
+
+func newRateLimiters(limits RateLimits) *rateLimiters {
+	return &rateLimiters{
+		ipMessages:    ratelimit.New[string](limits.IPMessages),
+		connections:   ratelimit.New[string](limits.Connections),
+		gameCreations: ratelimit.New[string](limits.GameCreations),
+	}
+}
+
+// wsSessionLimits are the buckets of the WebSocket connection.
+type wsSessionLimits struct {
+	messages   *ratelimit.Bucket
+	violations *ratelimit.Bucket
+}
+
+func (h *Handler) wsSetSessionLimits(session *melody.Session) {
+	session.Set("rate_limits", &wsSessionLimits{
+		messages:   ratelimit.NewBucket(h.rateLimits.Messages),
+		violations: ratelimit.NewBucket(h.rateLimits.Violations),
+	})
+}
+
+// wsAllowMessage reports whether the message of the session is within the limits,
+// it's checked before the message is decoded, so malformed messages are limited too.
+// Sessions that keep exceeding the limits are closed.
+func (h *Handler) wsAllowMessage(session *melody.Session) bool {
+	value, _ := session.Get("rate_limits")
+	limits, ok := value.(*wsSessionLimits)
+	if !ok {
+		h.log.Error("ws: can't get rate limits from session")
+		return true
+	}
+
+	ip := h.GetRemoteAddr(session.Request)
+	if limits.messages.Allow() && h.limiters.ipMessages.Allow(ip) {
+		return true
+	}
+
+	h.WsRespondErrorWithID(session, restapi.ErrRateLimited, "")
+
+	if limits.violations.Allow() {
+		return false
+	}
+
+	h.log.Warn("ws: closing flooding connection", slog.String("remote_addr", ip))
+	err := session.CloseWithMsg(melody.FormatCloseMessage(websocket.ClosePolicyViolation, restapi.ErrRateLimited.Error()))
+	if err != nil {
+		h.log.Error("can't close WebSocket session", slog.Any("error", err))
+	}
+	return false
+}
+
+// allowMessage shares the limit of the IP with its WebSocket messages.
+func (h *Handler) allowMessage(r *http.Request) bool {
+	return h.limiters.ipMessages.Allow(h.GetRemoteAddr(r))
+}
+
+func (h *Handler) allowConnection(r *http.Request) bool {
+	return h.limiters.connections.Allow(h.GetRemoteAddr(r))
+}
+
+func (h *Handler) allowGameCreation(r *http.Request) bool {
+	return h.limiters.gameCreations.Allow(h.GetRemoteAddr(r))
+}
@@ -0,0 +1,140 @@
+package gamesrest
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"dataxo-backend-game-ms/pkg/ratelimit"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type importGameUC struct {
+	GameUsecase
+}
+
+func (importGameUC) ImportGame(ctx context.Context, importer domain.PlayerID, text string) (*domain.Game, error) {
+	return &domain.Game{ID: uuid.New()}, nil
+}
+
+func TestHandler_GameCreationRateLimit(t *testing.T) {
+	responder := restapi.NewJsonResponder(nil, nil)
+	h := New(nil, importGameUC{}, responder, responder, WithRateLimits(RateLimits{
+		GameCreations: ratelimit.Limit{Rate: 0.001, Burst: 2},
+	}))
+	handler := h.ImportGame()
+
+	importFrom := func(addr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/api/v1/games/import", strings.NewReader("1. a1"))
+		r = r.WithContext(restapi.WithPlayerID(r.Context(), domain.PlayerID{ClientID: "alice"}))
+		r.RemoteAddr = addr
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	assert.Equal(t, http.StatusCreated, importFrom("10.0.0.1:1000").Code)
+	assert.Equal(t, http.StatusCreated, importFrom("10.0.0.1:1001").Code)
+
+	w := importFrom("10.0.0.1:1002")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code, "the limit is per IP, not per port")
+
+	httpErr := &restapi.HTTPError{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), httpErr))
+	assert.Equal(t, restapi.CodeRateLimited, httpErr.Code)
+
+	assert.Equal(t, http.StatusCreated, importFrom("10.0.0.2:1000").Code, "other IP")
+}
+
+func TestHandler_WsMalformedMessagesRateLimit(t *testing.T) {
+	responder := restapi.NewJsonResponder(nil, nil)
+	h := New(nil, spectatorGameUC{}, responder, responder, WithRateLimits(RateLimits{
+		Messages:   ratelimit.Limit{Rate: 0.001, Burst: 2},
+		Violations: ratelimit.Limit{Rate: 0.001, Burst: 1},
+	}))
+
+	server := newServer(h)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsGameURL(server, uuid.New()), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	codes := make([]restapi.ErrorCode, 4)
+	for i := range codes {
+		require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("not json")))
+
+		resp := &WsError{}
+		require.NoError(t, conn.ReadJSON(resp))
+		codes[i] = resp.Code
+	}
+	assert.Equal(t, []restapi.ErrorCode{restapi.CodeBadRequest, restapi.CodeBadRequest,
+		restapi.CodeRateLimited, restapi.CodeRateLimited}, codes)
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.ClosePolicyViolation), "close error: %v", err)
+}
+
+func TestHandler_MoveRateLimit(t *testing.T) {
+	responder := restapi.NewJsonResponder(nil, nil)
+	uc := &movesGameUC{game: &domain.Game{ID: uuid.New()}}
+	h := New(nil, uc, responder, responder, WithRateLimits(RateLimits{
+		IPMessages: ratelimit.Limit{Rate: 0.001, Burst: 1},
+	}))
+
+	server := newServer(h)
+	defer server.Close()
+
+	move := func() int {
+		resp, err := http.Post(server.URL+"/api/v1/games/"+uc.game.ID.String()+"/moves",
+			"application/json", strings.NewReader(`{"x":1,"y":1}`))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, move())
+	assert.Equal(t, http.StatusTooManyRequests, move())
+
+	conn, resp, err := websocket.DefaultDialer.Dial(wsGameURL(server, uc.game.ID), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+	require.Equal(t, http.StatusSwitchingProtocols, resp.StatusCode)
+
+	require.NoError(t, conn.WriteJSON(WsMuxReq{Type: "game", RequestID: "1", Message: WsRawMessage(`{"x":2,"y":2}`)}))
+	wsErr := &WsError{}
+	require.NoError(t, conn.ReadJSON(wsErr))
+	assert.Equal(t, restapi.CodeRateLimited, wsErr.Code, "the limit is shared with the WebSocket messages")
+}
+
+func TestHandler_GameEventsRateLimit(t *testing.T) {
+	responder := restapi.NewJsonResponder(nil, nil)
+	h := New(nil, spectatorGameUC{}, responder, responder, WithRateLimits(RateLimits{
+		Connections: ratelimit.Limit{Rate: 0.001, Burst: 1},
+	}))
+
+	server := newServer(h)
+	defer server.Close()
+
+	subscribe := func() int {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		r, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/games/"+uuid.NewString()+"/events", nil)
+		require.NoError(t, err)
+		resp, err := http.DefaultClient.Do(r)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	assert.Equal(t, http.StatusOK, subscribe())
+	assert.Equal(t, http.StatusTooManyRequests, subscribe())
+}
@@ -0,0 +1,66 @@
+package gamesrest
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+type SeriesScore struct {
+	ClientID string `json:"client_id"`
+	Wins     int    `json:"wins"`
+}
+
+type SeriesResp struct {
+	SeriesID uuid.UUID     `json:"series_id"`
+	BestOf   int           `json:"best_of"`
+	State    string        `json:"state"`
+	Score    []SeriesScore `json:"score"`
+	Draws    int           `json:"draws"`
+	GameIDs  []uuid.UUID   `json:"game_ids"`
+	Winner   string        `json:"winner,omitempty"`
+}
+
+func (r *SeriesResp) FromDomain(s *domain.Series) {
+	r.SeriesID = s.ID
+	r.BestOf = s.BestOf
+	r.State = s.State.String()
+	r.Draws = s.Draws
+	r.GameIDs = s.GameIDs
+
+	r.Score = make([]SeriesScore, len(s.Score))
+	for i := range s.Score {
+		r.Score[i] = SeriesScore{
+			ClientID: s.Score[i].PlayerID.ClientID,
+			Wins:     s.Score[i].Wins,
+		}
+	}
+
+	if s.Winner != nil {
+		r.Winner = s.Winner.ClientID
+	}
+}
+
+func (h *Handler) GetSeries() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		seriesID, err := uuid.Parse(chi.URLParam(r, "series_id"))
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		s, err := h.gameUC.GetSeries(r.Context(), seriesID)
+		if err != nil {
+			log.Error("uc get series", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := &SeriesResp{}
+		resp.FromDomain(s)
+		h.responder.Respond(w, http.StatusOK, resp)
+	}
+}
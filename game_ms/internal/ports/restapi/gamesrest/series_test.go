@@ -0,0 +1,65 @@
+package gamesrest
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+type seriesGameUC struct {
+	GameUsecase
+	series *domain.Series
+	err    error
+}
+
+func (uc *seriesGameUC) GetSeries(ctx context.Context, seriesID uuid.UUID) (*domain.Series, error) {
+	if uc.err != nil {
+		return nil, uc.err
+	}
+	if uc.series == nil || uc.series.ID != seriesID {
+		return nil, &domain.SeriesError{Err: domain.ErrNotFound, ID: seriesID}
+	}
+	return uc.series, nil
+}
+
+func TestHandler_GetSeries(t *testing.T) {
+	alice := domain.PlayerID{ClientID: "alice"}
+	s := &domain.Series{ID: uuid.New(), BestOf: 3, State: domain.Finished, Draws: 1, GameIDs: []uuid.UUID{uuid.New()},
+		Score: []domain.SeriesScore{{PlayerID: alice, Wins: 2}}, Winner: &alice}
+
+	w := serveGames(&seriesGameUC{series: s}, "/api/v1/series/"+s.ID.String())
+	require.Equal(t, http.StatusOK, w.Code)
+
+	resp := &SeriesResp{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+	assert.Equal(t, s.ID, resp.SeriesID)
+	assert.Equal(t, "finished", resp.State)
+	assert.Equal(t, []SeriesScore{{ClientID: "alice", Wins: 2}}, resp.Score)
+	assert.Equal(t, "alice", resp.Winner)
+
+	tcases := []struct {
+		Name   string
+		Target string
+		Err    error
+		Status int
+		Code   restapi.ErrorCode
+	}{
+		{Name: "invalid id", Target: "/api/v1/series/42", Status: http.StatusBadRequest, Code: restapi.CodeBadRequest},
+		{Name: "not found", Target: "/api/v1/series/" + uuid.NewString(), Status: http.StatusNotFound, Code: restapi.CodeNotFound},
+		{Name: "store error", Target: "/api/v1/series/" + uuid.NewString(), Err: errors.New("store is down"),
+			Status: http.StatusInternalServerError, Code: restapi.CodeInternal},
+	}
+
+	for _, tc := range tcases {
+		w := serveGames(&seriesGameUC{err: tc.Err}, tc.Target)
+		require.Equal(t, tc.Status, w.Code, tc.Name)
+		assert.Equal(t, tc.Code, errorCode(t, w), tc.Name)
+	}
+}
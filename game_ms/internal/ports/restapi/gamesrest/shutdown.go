@@ -0,0 +1,128 @@
+package gamesrest
+
+import (
+	"context"
+	"github.com/gorilla/websocket"
+	"github.com/olahol/melody"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// WsServerShutdownMessage is sent to every WebSocket session before the server closes it.
+type WsServerShutdownMessage struct {
+	Type string `json:"type"`
+	// milliseconds the client should wait before reconnecting
+	ReconnectAfter int64 `json:"reconnect_after"`
+}
+
+var ServerShutdownMessageType = "server_shutdown"
+
+// shutdown tracks the long-lived WebSocket and SSE connections,
+// http.Server.Shutdown doesn't wait for the hijacked ones and SSE streams never become idle.
+type shutdown struct {
+	// closed when the shutdown begins
+	done           chan struct{}
+	reconnectAfter time.Duration
+	conns          sync.WaitGroup
+	mu             sync.Mutex
+}
+
+func newShutdown() *shutdown {
+	return &shutdown{done: make(chan struct{})}
+}
+
+// acquire registers the connection, it returns false once the shutdown has begun.
+func (s *shutdown) acquire() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return false
+	default:
+	}
+
+	s.conns.Add(1)
+	return true
+}
+
+func (s *shutdown) release() {
+	s.conns.Done()
+}
+
+// begin returns false if the shutdown has already begun.
+func (s *shutdown) begin(reconnectAfter time.Duration) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case <-s.done:
+		return false
+	default:
+	}
+
+	s.reconnectAfter = reconnectAfter
+	close(s.done)
+	return true
+}
+
+// wait waits for the registered connections to finish.
+func (s *shutdown) wait(ctx context.Context) error {
+	finished := make(chan struct{})
+	go func() {
+		s.conns.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-finished:
+		return nil
+	}
+}
+
+// Shutdown tells the WebSocket clients to reconnect after reconnectAfter, closes their sessions
+// with the Service Restart code and ends the SSE streams with the same retry hint.
+// It waits for the connections to finish until ctx is done, new ones are refused meanwhile.
+func (h *Handler) Shutdown(ctx context.Context, reconnectAfter time.Duration) error {
+	if !h.shutdown.begin(reconnectAfter) {
+		return h.shutdown.wait(ctx)
+	}
+
+	sessions, err := h.wsHandler.Sessions()
+	if err != nil {
+		h.log.Error("ws shutdown: get sessions", slog.Any("error", err))
+		return err
+	}
+
+	h.log.Info("closing WebSocket sessions", slog.Int("sessions", len(sessions)))
+
+	msg := &WsServerShutdownMessage{
+		Type:           ServerShutdownMessageType,
+		ReconnectAfter: reconnectAfter.Milliseconds(),
+	}
+	for _, session := range sessions {
+		h.WsCodec(session).RespondWs(session, msg)
+	}
+
+	// the close message is queued after the shutdown one, so the clients get both
+	err = h.wsHandler.CloseWithMsg(melody.FormatCloseMessage(websocket.CloseServiceRestart, "server shutdown"))
+	if err != nil {
+		h.log.Error("ws shutdown: close sessions", slog.Any("error", err))
+		return err
+	}
+
+	return h.shutdown.wait(ctx)
+}
+
+// isShuttingDown reports whether the connections are being closed by Shutdown.
+func (h *Handler) isShuttingDown() bool {
+	select {
+	case <-h.shutdown.done:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,89 @@
+package gamesrest
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type spectatorGameUC struct {
+	GameUsecase
+}
+
+func (spectatorGameUC) GetGame(ctx context.Context, gameID uuid.UUID) (*domain.Game, error) {
+	return &domain.Game{ID: gameID}, nil
+}
+
+func (spectatorGameUC) GetSide(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID) (domain.Side, error) {
+	return domain.NoneSide, nil
+}
+
+// newServer serves the routes of the handler for alice.
+func newServer(h *Handler) *httptest.Server {
+	router := chi.NewRouter()
+	router.Use(func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := restapi.WithPlayerID(r.Context(), domain.PlayerID{ClientID: "alice"})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	})
+	h.SetupRoutes(router)
+	return httptest.NewServer(router)
+}
+
+func wsGameURL(server *httptest.Server, gameID uuid.UUID) string {
+	return "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/games/" + gameID.String() + "/ws"
+}
+
+func TestHandler_Shutdown(t *testing.T) {
+	responder := restapi.NewJsonResponder(nil, nil)
+	h := New(nil, spectatorGameUC{}, responder, responder)
+
+	server := newServer(h)
+	defer server.Close()
+
+	url := wsGameURL(server, uuid.New())
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// the session is registered when the handshake is done, Sessions can still miss it
+	require.Eventually(t, func() bool { return h.wsHandler.Len() == 1 }, time.Second, 10*time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	shutdownErr := make(chan error, 1)
+	go func() {
+		shutdownErr <- h.Shutdown(ctx, 3*time.Second)
+	}()
+
+	_, bytes, err := conn.ReadMessage()
+	require.NoError(t, err)
+
+	msg := &WsServerShutdownMessage{}
+	require.NoError(t, json.Unmarshal(bytes, msg))
+	assert.Equal(t, ServerShutdownMessageType, msg.Type)
+	assert.EqualValues(t, 3000, msg.ReconnectAfter)
+
+	_, _, err = conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseServiceRestart), "close error: %v", err)
+	_ = conn.Close()
+
+	require.NoError(t, <-shutdownErr, "the session is finished")
+
+	_, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode, "new connections are refused")
+}
@@ -0,0 +1,180 @@
+package gamesrest
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"dataxo-backend-game-ms/pkg/eventhub"
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const sseKeepAlivePeriod = 15 * time.Second
+
+// GameEvents streams the game broadcasts as Server-Sent Events.
+// A reconnecting client gets the missed events after its Last-Event-ID,
+// or the game state if some of them aren't buffered anymore.
+// The stream of the finished game has its state only.
+func (h *Handler) GameEvents() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+
+		gameID, err := uuid.Parse(chi.URLParam(r, "game_id"))
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		_, err = h.gameUC.GetGame(ctx, gameID)
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusNotFound, err)
+			return
+		}
+
+		var lastID int64
+		if lastIDHeader := r.Header.Get("Last-Event-ID"); lastIDHeader != "" {
+			lastID, err = strconv.ParseInt(lastIDHeader, 10, 64)
+			if err != nil {
+				h.responder.RespondError(w, r, http.StatusBadRequest, err)
+				return
+			}
+		}
+
+		if !h.allowConnection(r) {
+			h.responder.RespondError(w, r, http.StatusTooManyRequests, restapi.ErrRateLimited)
+			return
+		}
+
+		if !h.shutdown.acquire() {
+			h.responder.RespondError(w, r, http.StatusServiceUnavailable, restapi.ErrShuttingDown)
+			return
+		}
+		defer h.shutdown.release()
+
+		rc := http.NewResponseController(w)
+		err = rc.SetWriteDeadline(time.Time{})
+		if err != nil {
+			log.Warn("sse: can't disable write deadline", slog.Any("error", err))
+		}
+
+		// the state is in line with the subscription while the game is locked
+		unlock := h.gameLocks.lock(gameID)
+
+		g, err := h.gameUC.GetGame(ctx, gameID)
+		if err != nil {
+			unlock()
+			log.Error("sse: get game", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		// the finished game gets no more events, its topic is removed
+		finished := g.State == domain.Finished
+
+		var sub *eventhub.Subscription
+		var missed []eventhub.Event
+		complete := false
+		if !finished {
+			sub, missed, complete = h.events.Subscribe(gameID, lastID)
+			defer h.events.Unsubscribe(gameID, sub)
+		}
+
+		var state *WsGameStateResp
+		if !complete {
+			resp, err := h.GetGameResp(ctx, gameID)
+			if err != nil {
+				unlock()
+				log.Error("sse: get game", slog.Any("error", err))
+				h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+				return
+			}
+
+			state = &WsGameStateResp{Type: GameStateResponseType, Seq: h.events.LastID(gameID), GameResp: resp}
+			missed = nil
+		}
+		unlock()
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+
+		if state != nil {
+			if err := writeSSEEvent(w, eventhub.Event{ID: state.Seq, Data: state}); err != nil {
+				log.Debug("sse: write state", slog.Any("error", err))
+				return
+			}
+		}
+		for _, event := range missed {
+			if err := writeSSEEvent(w, event); err != nil {
+				log.Debug("sse: write missed event", slog.Any("error", err))
+				return
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			log.Error("sse: flush", slog.Any("error", err))
+			return
+		}
+
+		if finished {
+			// the stream is closed after the final state
+			return
+		}
+
+		keepAlive := time.NewTicker(sseKeepAlivePeriod)
+		defer keepAlive.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-h.shutdown.done:
+				// the client reconnects with Last-Event-ID after the retry delay
+				_, err = fmt.Fprintf(w, "retry: %d\n\n", h.shutdown.reconnectAfter.Milliseconds())
+				if err == nil {
+					err = rc.Flush()
+				}
+				if err != nil {
+					log.Debug("sse: write retry", slog.Any("error", err))
+				}
+				return
+			case <-keepAlive.C:
+				_, err = fmt.Fprint(w, ": keep-alive\n\n")
+			case event, ok := <-sub.C:
+				if !ok {
+					// the client is too slow, it will reconnect with Last-Event-ID
+					return
+				}
+				// the events published before the state was taken are in it
+				if state != nil && event.ID <= state.Seq {
+					continue
+				}
+				err = writeSSEEvent(w, event)
+			}
+
+			if err == nil {
+				err = rc.Flush()
+			}
+			if err != nil {
+				log.Debug("sse: write event", slog.Any("error", err))
+				return
+			}
+		}
+	}
+}
+
+func writeSSEEvent(w http.ResponseWriter, event eventhub.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", event.ID, data)
+	return err
+}
@@ -0,0 +1,71 @@
+package gamesrest
+
+import (
+	"bufio"
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"encoding/json"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+type finishedGameStateUC struct {
+	GameUsecase
+}
+
+func (finishedGameStateUC) GetGame(ctx context.Context, gameID uuid.UUID) (*domain.Game, error) {
+	return &domain.Game{ID: gameID, State: domain.Finished, Winner: domain.XWin}, nil
+}
+
+func TestHandler_GameEventsOfFinishedGame(t *testing.T) {
+	responder := restapi.NewJsonResponder(nil, nil)
+	h := New(nil, finishedGameStateUC{}, responder, responder)
+
+	server := newServer(h)
+	defer server.Close()
+
+	gameID := uuid.New()
+	for _, lastID := range []string{"", "0", "5"} {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+		r, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/api/v1/games/"+gameID.String()+"/events", nil)
+		require.NoError(t, err)
+		if lastID != "" {
+			r.Header.Set("Last-Event-ID", lastID)
+		}
+
+		resp, err := http.DefaultClient.Do(r)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// the stream is closed after the state
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err, "Last-Event-ID %q", lastID)
+		_ = resp.Body.Close()
+		cancel()
+
+		var data []string
+		scanner := bufio.NewScanner(strings.NewReader(string(body)))
+		for scanner.Scan() {
+			if line, ok := strings.CutPrefix(scanner.Text(), "data: "); ok {
+				data = append(data, line)
+			}
+		}
+		require.Len(t, data, 1, "Last-Event-ID %q", lastID)
+
+		state := &WsGameStateResp{}
+		require.NoError(t, json.Unmarshal([]byte(data[0]), state))
+		assert.Equal(t, GameStateResponseType, state.Type)
+		assert.Equal(t, gameID, state.GameID)
+		assert.Equal(t, domain.Finished.String(), state.State)
+	}
+
+	assert.Zero(t, h.events.LastID(gameID), "the topic of the finished game isn't recreated")
+}
@@ -0,0 +1,73 @@
+package gamesrest
+
+import (
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"github.com/gorilla/websocket"
+	"github.com/olahol/melody"
+	"github.com/vmihailenco/msgpack/v5"
+	"net/http"
+)
+
+const (
+	JsonSubprotocol    = "dataxo.json"
+	MsgpackSubprotocol = "dataxo.msgpack"
+)
+
+// WsCodec returns the codec of the subprotocol negotiated by the session.
+func (h *Handler) WsCodec(session *melody.Session) restapi.WsCodec {
+	conn := session.WebsocketConnection()
+	if conn == nil {
+		return h.wsResponder
+	}
+
+	codec, ok := h.wsCodecs[conn.Subprotocol()]
+	if !ok {
+		return h.wsResponder
+	}
+
+	return codec
+}
+
+// wsSelectSubprotocol returns the first subprotocol offered by the client that has a codec,
+// the client lists the subprotocols in the order of its preference.
+func (h *Handler) wsSelectSubprotocol(r *http.Request) string {
+	for _, subprotocol := range websocket.Subprotocols(r) {
+		if _, ok := h.wsCodecs[subprotocol]; ok {
+			return subprotocol
+		}
+	}
+	return ""
+}
+
+// WsRawMessage is a raw encoded message payload.
+// It delays decoding like json.RawMessage for both JSON and MessagePack.
+type WsRawMessage []byte
+
+func (m WsRawMessage) MarshalJSON() ([]byte, error) {
+	if m == nil {
+		return []byte("null"), nil
+	}
+	return m, nil
+}
+
+func (m *WsRawMessage) UnmarshalJSON(data []byte) error {
+	*m = append((*m)[0:0], data...)
+	return nil
+}
+
+func (m WsRawMessage) EncodeMsgpack(enc *msgpack.Encoder) error {
+	if m == nil {
+		return enc.EncodeNil()
+	}
+	_, err := enc.Writer().Write(m)
+	return err
+}
+
+func (m *WsRawMessage) DecodeMsgpack(dec *msgpack.Decoder) error {
+	raw, err := dec.DecodeRaw()
+	if err != nil {
+		return err
+	}
+	*m = WsRawMessage(raw)
+	return nil
+}
@@ -0,0 +1,163 @@
+package gamesrest
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"dataxo-backend-game-ms/pkg/ratelimit"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"reflect"
+	"testing"
+)
+
+func wsMessageSamples() []interface{} {
+	moves := []domain.Move{
+		{ID: 1, InGameID: 0, X: 1, Y: 2, TimesUsed: 1, Side: domain.XSide},
+		{ID: 2, InGameID: 1, X: 0, Y: 0, TimesUsed: 2, Side: domain.OSide},
+	}
+	moveEvents := []MoveEvent{
+		{Type: "place_move", MoveID: 3, X: 1, Y: 1, Side: domain.XSide, TimesUsed: 1},
+		{Type: "remove_move", MoveID: 0, X: 1, Y: 2, Side: domain.XSide, TimesUsed: 1},
+	}
+	series := &SeriesResp{
+		SeriesID: uuid.New(),
+		BestOf:   3,
+		State:    domain.Started.String(),
+		Score:    []SeriesScore{{ClientID: "alice", Wins: 1}, {ClientID: "bob", Wins: 0}},
+		Draws:    1,
+		GameIDs:  []uuid.UUID{uuid.New(), uuid.New()},
+	}
+
+	return []interface{}{
+		&WsHelloReq{Version: 1, Features: []string{"takeback"}},
+		&WsSyncReq{LastSeq: 12},
+		&WsGameReq{MoveID: 4, X: 2, Y: 3},
+		&WsPresenceReq{Action: "join"},
+		&WsTakebackReq{Action: "propose"},
+		&WsError{Error: "It's not your turn", Code: restapi.CodeNotYourTurn, ResponseForID: "1", NeedReSync: true},
+		&WsHelloResp{Type: HelloResponseType, ResponseForID: "1", Version: 1,
+			SupportedVersions: []int{1}, Features: []string{"draw"}},
+		&WsSideResponse{Type: SideMessageType, ResponseForID: "2", Side: domain.OSide},
+		&WsCreateResponse{Type: CreateMessageType, GameID: uuid.NewString(), Side: int(domain.XSide)},
+		&WsGameStateResp{
+			Type:          GameStateResponseType,
+			ResponseForID: "3",
+			Seq:           5,
+			GameResp: GameResp{
+				GameID: uuid.New(),
+				Mode:   domain.ModeWithFriend,
+				Config: WsGameConfig{PlayerFiguresLimit: 6, WinLineLength: 4, BoardWidth: 4, BoardHeight: 4},
+				State:  domain.Finished.String(),
+				Players: GamePlayers{
+					X: &GamePlayer{ClientID: "alice", Ready: true},
+				},
+				Moves:       moves,
+				WinSequence: moves[:1],
+				Winner:      domain.XWin,
+				Reason:      domain.WinLineFinish.String(),
+				SeriesID:    series.SeriesID.String(),
+				Series:      series,
+			},
+		},
+		&WsSyncResp{Type: SyncResponseType, ResponseForID: "4", Seq: 40,
+			State: &GameResp{GameID: uuid.New(), Mode: domain.ModeWithFriend, State: domain.Started.String(),
+				Moves: moves, Reason: domain.NotFinished.String()}},
+		&WsGameResp{Type: SuccessMoveResponseType, ResponseForID: "req-1"},
+		&WsSuccessResp{Type: SuccessResponseType, ResponseForID: "req-2"},
+		&WsMoveBroadcast{WsBroadcastMeta: WsBroadcastMeta{RequestID: "req-1", Seq: 7},
+			Type: MoveBroadcastType, MoveEvents: moveEvents},
+		&WsGameStartBroadcast{Type: GameStartBroadcastType},
+		&WsPresenceBroadcast{Type: PresenceBroadcastType, ClientID: "bob", Online: true},
+		&WsServerShutdownMessage{Type: ServerShutdownMessageType, ReconnectAfter: 5000},
+		&WsGameFinishBroadcast{Type: GameFinishBroadcastType, Winner: domain.Draw,
+			Reason: domain.DrawAgreementFinish.String(), NextGameID: uuid.NewString()},
+		&WsTakebackProposalBroadcast{Type: TakebackProposalBroadcastType, Side: domain.XSide},
+		&WsTakebackDeclineBroadcast{Type: TakebackDeclineBroadcastType},
+		&WsTakebackBroadcast{Type: TakebackBroadcastType, MoveEvents: moveEvents},
+		&WsDrawOfferBroadcast{Type: DrawOfferBroadcastType, Side: domain.OSide},
+		&WsDrawDeclineBroadcast{Type: DrawDeclineBroadcastType},
+	}
+}
+
+func TestWsCodecs_RoundTrip(t *testing.T) {
+	codecs := map[string]restapi.WsCodec{
+		JsonSubprotocol:    restapi.NewJsonResponder(nil, nil),
+		MsgpackSubprotocol: restapi.NewMsgpackResponder(nil, nil),
+	}
+
+	for name, codec := range codecs {
+		for _, sample := range wsMessageSamples() {
+			typeName := fmt.Sprintf("%v %T", name, sample)
+
+			data, err := codec.Marshal(sample)
+			require.NoError(t, err, typeName)
+
+			decoded := reflect.New(reflect.TypeOf(sample).Elem()).Interface()
+			require.NoError(t, codec.Unmarshal(data, decoded), typeName)
+
+			assert.Equal(t, sample, decoded, typeName)
+		}
+	}
+}
+
+func TestWsCodecs_MuxMessage(t *testing.T) {
+	codecs := map[string]restapi.WsCodec{
+		JsonSubprotocol:    restapi.NewJsonResponder(nil, nil),
+		MsgpackSubprotocol: restapi.NewMsgpackResponder(nil, nil),
+	}
+
+	for name, codec := range codecs {
+		data, err := codec.Marshal(map[string]interface{}{
+			"type":       "game",
+			"request_id": "42",
+			"message":    map[string]interface{}{"move_id": 5, "x": 1, "y": 3},
+		})
+		require.NoError(t, err, name)
+
+		req := &WsMuxReq{}
+		require.NoError(t, codec.Unmarshal(data, req), name)
+		assert.Equal(t, "game", req.Type, name)
+		assert.Equal(t, "42", req.RequestID, name)
+
+		gameReq := &WsGameReq{}
+		require.NoError(t, codec.Unmarshal(req.Message, gameReq), name)
+		assert.Equal(t, &WsGameReq{MoveID: 5, X: 1, Y: 3}, gameReq, name)
+	}
+}
+
+func TestHandler_WsSubprotocolNegotiation(t *testing.T) {
+	limits := DefaultRateLimits()
+	limits.Connections = ratelimit.Limit{Rate: 100, Burst: 100}
+
+	responder := restapi.NewJsonResponder(nil, nil)
+	h := New(nil, spectatorGameUC{}, responder, responder, WithRateLimits(limits),
+		WithWsCodec(MsgpackSubprotocol, restapi.NewMsgpackResponder(nil, nil)))
+
+	server := newServer(h)
+	defer server.Close()
+
+	url := wsGameURL(server, uuid.New())
+
+	tcases := []struct {
+		Name     string
+		Offered  []string
+		Selected string
+	}{
+		{Name: "msgpack is preferred", Offered: []string{MsgpackSubprotocol, JsonSubprotocol}, Selected: MsgpackSubprotocol},
+		{Name: "json is preferred", Offered: []string{JsonSubprotocol, MsgpackSubprotocol}, Selected: JsonSubprotocol},
+		{Name: "unknown is skipped", Offered: []string{"dataxo.xml", MsgpackSubprotocol}, Selected: MsgpackSubprotocol},
+		{Name: "msgpack only", Offered: []string{MsgpackSubprotocol}, Selected: MsgpackSubprotocol},
+		{Name: "unknown", Offered: []string{"dataxo.xml"}, Selected: ""},
+	}
+
+	for _, tc := range tcases {
+		dialer := websocket.Dialer{Subprotocols: tc.Offered}
+		conn, _, err := dialer.Dial(url, nil)
+		require.NoError(t, err, tc.Name)
+		assert.Equal(t, tc.Selected, conn.Subprotocol(), tc.Name)
+		_ = conn.Close()
+	}
+}
@@ -0,0 +1,39 @@
+package gamesrest
+
+import "time"
+
+// WsConfig tunes the WebSocket connections.
+type WsConfig struct {
+	// larger messages close the connection
+	MaxMessageSize int64
+	WriteWait      time.Duration
+	// the connection without pong for this duration is considered dead,
+	// so the player becomes offline
+	PongWait time.Duration
+	// must be less than PongWait
+	PingPeriod time.Duration
+}
+
+func DefaultWsConfig() WsConfig {
+	return WsConfig{
+		MaxMessageSize: 4 << 10,
+		WriteWait:      10 * time.Second,
+		PongWait:       30 * time.Second,
+		PingPeriod:     25 * time.Second,
+	}
+}
+
+// WithWsConfig replaces DefaultWsConfig.
+func WithWsConfig(cfg WsConfig) Opt {
+	return func(h *Handler) {
+		h.wsConfig = cfg
+	}
+}
+
+func (h *Handler) applyWsConfig() {
+	cfg := h.wsHandler.Config
+	cfg.MaxMessageSize = h.wsConfig.MaxMessageSize
+	cfg.WriteWait = h.wsConfig.WriteWait
+	cfg.PongWait = h.wsConfig.PongWait
+	cfg.PingPeriod = h.wsConfig.PingPeriod
+}
@@ -0,0 +1,86 @@
+package gamesrest
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"github.com/google/uuid"
+	"github.com/olahol/melody"
+)
+
+type WsDrawOfferBroadcast struct {
+	WsBroadcastMeta
+
+	Type string      `json:"type"`
+	Side domain.Side `json:"side"`
+}
+
+type WsDrawDeclineBroadcast struct {
+	WsBroadcastMeta
+
+	Type string `json:"type"`
+}
+
+var (
+	DrawOfferBroadcastType   = "draw_offer_broadcast"
+	DrawDeclineBroadcastType = "draw_decline_broadcast"
+)
+
+func (h *Handler) WsOfferDraw(session *melody.Session, requestID string, gameID uuid.UUID, bytes []byte) {
+	ctx := session.Request.Context()
+
+	side, err := h.gameUC.OfferDraw(ctx, gameID, h.WsGetPlayerID(session))
+	if err != nil {
+		h.WsRespondErrorWithID(session, err, requestID)
+		return
+	}
+
+	h.WsRespondSuccess(session, requestID)
+
+	h.WsBroadcastToGame(gameID, requestID, &WsDrawOfferBroadcast{
+		Type: DrawOfferBroadcastType,
+		Side: side,
+	})
+}
+
+func (h *Handler) WsAcceptDraw(session *melody.Session, requestID string, gameID uuid.UUID, bytes []byte) {
+	ctx := session.Request.Context()
+
+	res, err := h.gameUC.AnswerDraw(ctx, gameID, h.WsGetPlayerID(session), true)
+	if err != nil {
+		h.WsRespondErrorWithID(session, err, requestID)
+		return
+	}
+
+	h.WsRespondSuccess(session, requestID)
+
+	h.WsBroadcastGameFinish(gameID, requestID, res)
+}
+
+func (h *Handler) WsDeclineDraw(session *melody.Session, requestID string, gameID uuid.UUID, bytes []byte) {
+	ctx := session.Request.Context()
+
+	_, err := h.gameUC.AnswerDraw(ctx, gameID, h.WsGetPlayerID(session), false)
+	if err != nil {
+		h.WsRespondErrorWithID(session, err, requestID)
+		return
+	}
+
+	h.WsRespondSuccess(session, requestID)
+
+	h.WsBroadcastToGame(gameID, requestID, &WsDrawDeclineBroadcast{
+		Type: DrawDeclineBroadcastType,
+	})
+}
+
+func (h *Handler) WsResign(session *melody.Session, requestID string, gameID uuid.UUID, bytes []byte) {
+	ctx := session.Request.Context()
+
+	res, err := h.gameUC.Resign(ctx, gameID, h.WsGetPlayerID(session))
+	if err != nil {
+		h.WsRespondErrorWithID(session, err, requestID)
+		return
+	}
+
+	h.WsRespondSuccess(session, requestID)
+
+	h.WsBroadcastGameFinish(gameID, requestID, res)
+}
@@ -2,7 +2,6 @@ package gamesrest
 
 import (
 	"dataxo-backend-game-ms/internal/domain"
-	"encoding/json"
 	"github.com/google/uuid"
 	"github.com/olahol/melody"
 	"log/slog"
@@ -28,22 +27,25 @@ type MoveEvent struct {
 	TimesUsed int         `json:"times_used"`
 }
 
-func (e *MoveEvent) FromDomain(event domain.MoveEvent) {
-	t := ""
-	switch event.Type {
+func MoveEventTypeFromDomain(t domain.MoveEventType) string {
+	switch t {
 	case domain.PlaceMove:
-		t = "place_move"
+		return "place_move"
 	case domain.RemoveMove:
-		t = "remove_move"
+		return "remove_move"
 	case domain.HeatMove:
-		t = "heat_move"
+		return "heat_move"
 	case domain.BlockMove:
-		t = "block_move"
+		return "block_move"
+	default:
+		return ""
 	}
+}
 
+func (e *MoveEvent) FromDomain(event domain.MoveEvent) {
 	move := event.Move
 
-	e.Type = t
+	e.Type = MoveEventTypeFromDomain(event.Type)
 	e.MoveID = move.InGameID
 	e.X = move.X
 	e.Y = move.Y
@@ -62,12 +64,50 @@ func MoveEventsFromDomain(events []domain.MoveEvent) []MoveEvent {
 }
 
 type WsMoveBroadcast struct {
+	WsBroadcastMeta
+
 	Type       string      `json:"type"`
 	MoveEvents []MoveEvent `json:"move_events"`
 }
 
 type WsGameFinishBroadcast struct {
-	Type string `json:"type"`
+	WsBroadcastMeta
+
+	Type       string         `json:"type"`
+	Winner     domain.WinSide `json:"winner"`
+	Reason     string         `json:"reason"`
+	NextGameID string         `json:"next_game_id,omitempty"`
+	// empty if the game isn't rated
+	RatingChanges []RatingChangeResp `json:"rating_changes,omitempty"`
+}
+
+type RatingChangeResp struct {
+	ClientID string `json:"client_id"`
+	Pool     string `json:"pool"`
+	Before   int    `json:"before"`
+	After    int    `json:"after"`
+	Delta    int    `json:"delta"`
+}
+
+func (r *RatingChangeResp) FromDomain(change domain.RatingChange) {
+	r.ClientID = change.PlayerID.ClientID
+	r.Pool = change.Pool
+	r.Before = change.Before
+	r.After = change.After
+	r.Delta = change.Delta()
+}
+
+func RatingChangesFromDomain(changes []domain.RatingChange) []RatingChangeResp {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	converted := make([]RatingChangeResp, len(changes))
+	for i := range changes {
+		converted[i].FromDomain(changes[i])
+	}
+
+	return converted
 }
 
 var (
@@ -80,13 +120,12 @@ func (h *Handler) WsGame(session *melody.Session, requestID string, gameID uuid.
 	ctx := session.Request.Context()
 
 	req := &WsGameReq{}
-	if err := json.Unmarshal(bytes, req); err != nil {
+	if err := h.WsCodec(session).Unmarshal(bytes, req); err != nil {
 		h.WsRespondErrorWithID(session, err, requestID)
 		return
 	}
 
 	h.log.Debug("WebSocket Game Request",
-		slog.String("json", string(bytes)),
 		slog.Any("struct", req),
 	)
 
@@ -108,43 +147,40 @@ func (h *Handler) WsGame(session *melody.Session, requestID string, gameID uuid.
 		return
 	}
 
-	/*
-		h.wsResponder.RespondWs(session, WsGameResp{
-			Type:          SuccessMoveResponseType,
-			ResponseForID: requestID,
-		})
-	*/
-
-	data, _ := h.wsResponder.Marshal(WsMoveBroadcast{
-		Type:       MoveBroadcastType,
-		MoveEvents: MoveEventsFromDomain(res.Events),
+	h.WsCodec(session).RespondWs(session, WsGameResp{
+		Type:          SuccessMoveResponseType,
+		ResponseForID: requestID,
 	})
 
-	err = h.wsHandler.BroadcastFilter(data, func(other *melody.Session) bool {
-		otherGameID, err := h.WsGameIDFromSession(other)
-		if err != nil {
-			h.log.Error("game move broadcast", slog.Any("error", err))
-		}
+	h.WsBroadcastMove(gameID, requestID, res)
+}
 
-		if gameID == otherGameID {
-			return true
-		}
-		return false
+// WsBroadcastMove sends the move events and the game finish if any to the game sessions.
+func (h *Handler) WsBroadcastMove(gameID uuid.UUID, requestID string, res domain.MakeMoveResult) {
+	h.WsBroadcastToGame(gameID, requestID, &WsMoveBroadcast{
+		Type:       MoveBroadcastType,
+		MoveEvents: MoveEventsFromDomain(res.Events),
 	})
-	if err != nil {
-		h.log.Error("ws move broadcast", slog.Any("error", err))
-	}
 
 	if !res.GameFinished {
 		return
 	}
 
-	data, _ = h.wsResponder.Marshal(WsGameFinishBroadcast{
-		Type: GameFinishBroadcastType,
-	})
+	h.WsBroadcastGameFinish(gameID, requestID, res)
+}
 
-	err = h.wsHandler.Broadcast(data)
-	if err != nil {
-		h.log.Error("ws game finish broadcast", slog.Any("error", err))
+func (h *Handler) WsBroadcastGameFinish(gameID uuid.UUID, requestID string, res domain.MakeMoveResult) {
+	finish := &WsGameFinishBroadcast{
+		Type:          GameFinishBroadcastType,
+		Winner:        res.Winner,
+		Reason:        res.FinishReason.String(),
+		RatingChanges: RatingChangesFromDomain(res.RatingChanges),
+	}
+	if res.NextGameID != uuid.Nil {
+		finish.NextGameID = res.NextGameID.String()
 	}
+
+	h.WsBroadcastToGame(gameID, requestID, finish)
+	// the finished game gets no more broadcasts, reconnecting clients get its state
+	h.events.Remove(gameID)
 }
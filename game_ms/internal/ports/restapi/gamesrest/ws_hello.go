@@ -0,0 +1,67 @@
+package gamesrest
+
+import (
+	"github.com/google/uuid"
+	"github.com/olahol/melody"
+	"log/slog"
+	"slices"
+)
+
+type WsHelloReq struct {
+	Version  int      `json:"version"`
+	Features []string `json:"features"`
+}
+
+type WsHelloResp struct {
+	Type          string `json:"type"`
+	ResponseForID string `json:"response_for_id"`
+
+	Version           int      `json:"version"`
+	SupportedVersions []int    `json:"supported_versions"`
+	Features          []string `json:"features"`
+}
+
+var HelloResponseType = "hello_response"
+
+// WsHello negotiates the protocol version of the session. Features in the
+// response are the server features of the version that the client also has
+// stated, or all of them if the client hasn't stated any.
+// Messages of the other features are rejected, see MessageFeatures.
+func (h *Handler) WsHello(session *melody.Session, requestID string, gameID uuid.UUID, bytes []byte) {
+	req := &WsHelloReq{}
+	if err := h.WsCodec(session).Unmarshal(bytes, req); err != nil {
+		h.WsRespondErrorWithID(session, err, requestID)
+		return
+	}
+
+	h.log.Debug("WebSocket Hello Request",
+		slog.Any("struct", req),
+	)
+
+	version, ok := NegotiateProtocolVersion(req.Version)
+	if !ok {
+		h.WsRespondErrorWithID(session, &ProtocolVersionError{
+			Err:     ErrUnsupportedProtocolVersion,
+			Version: req.Version,
+		}, requestID)
+		return
+	}
+
+	features := ProtocolFeatures[version]
+	if len(req.Features) > 0 {
+		features = slices.DeleteFunc(slices.Clone(features), func(feature string) bool {
+			return !slices.Contains(req.Features, feature)
+		})
+	}
+
+	session.Set("protocol_version", version)
+	session.Set("features", features)
+
+	h.WsCodec(session).RespondWs(session, WsHelloResp{
+		Type:              HelloResponseType,
+		ResponseForID:     requestID,
+		Version:           version,
+		SupportedVersions: SupportedProtocolVersions,
+		Features:          features,
+	})
+}
@@ -0,0 +1,58 @@
+package gamesrest
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+type finishedGameUC struct {
+	spectatorGameUC
+}
+
+func (finishedGameUC) OfferDraw(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID) (domain.Side, error) {
+	return domain.NoneSide, domain.ErrGameFinished
+}
+
+func TestHandler_WsFeatures(t *testing.T) {
+	responder := restapi.NewJsonResponder(nil, nil)
+	h := New(nil, finishedGameUC{}, responder, responder)
+
+	server := newServer(h)
+	defer server.Close()
+
+	url := wsGameURL(server, uuid.New())
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	send := func(req WsMuxReq) {
+		require.NoError(t, conn.WriteJSON(req))
+	}
+	readError := func() *WsError {
+		resp := &WsError{}
+		require.NoError(t, conn.ReadJSON(resp))
+		return resp
+	}
+
+	send(WsMuxReq{Type: "offer_draw", RequestID: "1"})
+	assert.Equal(t, restapi.CodeGameFinished, readError().Code, "features are enabled without hello")
+
+	send(WsMuxReq{Type: "hello", RequestID: "2", Message: WsRawMessage(`{"version":1,"features":["draw"]}`)})
+	hello := &WsHelloResp{}
+	require.NoError(t, conn.ReadJSON(hello))
+	assert.Equal(t, []string{"draw"}, hello.Features)
+
+	send(WsMuxReq{Type: "takeback", RequestID: "3", Message: WsRawMessage(`{"action":"propose"}`)})
+	resp := readError()
+	assert.Equal(t, restapi.CodeFeatureNotNegotiated, resp.Code)
+	assert.Equal(t, "3", resp.ResponseForID)
+
+	send(WsMuxReq{Type: "offer_draw", RequestID: "4"})
+	assert.Equal(t, restapi.CodeGameFinished, readError().Code, "negotiated feature is routed")
+}
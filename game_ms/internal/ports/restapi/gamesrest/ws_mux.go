@@ -3,19 +3,21 @@ package gamesrest
 import (
 	"context"
 	"dataxo-backend-game-ms/internal/domain"
-	"encoding/json"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"errors"
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 	"github.com/olahol/melody"
 	"log/slog"
+	"net"
 	"net/http"
 )
 
 type WsMuxReq struct {
-	Type      string          `json:"type"`
-	RequestID string          `json:"request_id"`
-	ClientID  string          `json:"client_id"`
-	Message   json.RawMessage `json:"message"`
+	Type      string       `json:"type"`
+	RequestID string       `json:"request_id"`
+	Message   WsRawMessage `json:"message"`
 }
 
 type WsSideResponse struct {
@@ -39,6 +41,9 @@ func (h *Handler) WsMux() http.HandlerFunc {
 		h.log.Debug("WebSocket Connected",
 			slog.String("remote_addr", session.RemoteAddr().String()),
 		)
+		if h.metrics != nil {
+			h.metrics.WsSessionOpened()
+		}
 		ctx := session.Request.Context()
 
 		gameIDParam := chi.URLParam(session.Request, "game_id")
@@ -54,16 +59,20 @@ func (h *Handler) WsMux() http.HandlerFunc {
 			return
 		}
 
-		clientID := chi.URLParam(session.Request, "client_id")
-		session.Set("client_id", clientID)
+		// the player is bound to the connection for its lifetime
+		player, _ := restapi.PlayerIDFromContext(ctx)
+		session.Set("client_id", player.ClientID)
+		h.wsSetSessionLimits(session)
 
 		// TODO: redo this, it's just workaround
 		if gameIDParam == "create" {
-			player := domain.PlayerID{
-				ClientID: clientID,
-			}
 			h.log.Debug("create game", slog.Any("playerID", player))
 
+			if !h.allowGameCreation(session.Request) {
+				h.RespondErrorWsAndClose(session, "", restapi.ErrRateLimited, h.log)
+				return
+			}
+
 			g, err := h.gameUC.CreateGame(ctx, player, domain.ModeWithFriend,
 				domain.ModeParams{MySide: domain.RandomSideRequest})
 			if err != nil {
@@ -84,7 +93,7 @@ func (h *Handler) WsMux() http.HandlerFunc {
 				GameID: g.ID.String(),
 				Side:   int(side),
 			}
-			h.wsResponder.RespondWs(session, resp)
+			h.WsCodec(session).RespondWs(session, resp)
 
 			gameID = g.ID
 		}
@@ -92,54 +101,65 @@ func (h *Handler) WsMux() http.HandlerFunc {
 		_, err = h.gameUC.GetGame(ctx, gameID)
 		if err != nil {
 			h.RespondErrorWsAndClose(session, "", err, h.log)
+			return
 		}
 
 		session.Set("game_id", gameID)
+		h.wsPresenceConnected(session, gameID)
 	})
 
-	h.wsHandler.HandleMessage(func(session *melody.Session, bytes []byte) {
-		req := &WsMuxReq{}
-		if err := json.Unmarshal(bytes, req); err != nil {
-			h.wsResponder.RespondErrorWs(session, err)
-			return
+	h.wsHandler.HandleMessage(h.wsHandleMessage)
+	h.wsHandler.HandleMessageBinary(h.wsHandleMessage)
+
+	h.wsHandler.HandleError(func(session *melody.Session, err error) {
+		var netErr net.Error
+		switch {
+		case errors.As(err, &netErr) && netErr.Timeout():
+			// the read deadline is extended by pongs only
+			h.log.Info("WebSocket connection didn't answer pings",
+				slog.String("remote_addr", session.RemoteAddr().String()),
+			)
+		case errors.Is(err, websocket.ErrReadLimit):
+			h.log.Warn("WebSocket message is too large",
+				slog.String("remote_addr", session.RemoteAddr().String()),
+			)
+		case websocket.IsUnexpectedCloseError(err, websocket.CloseNormalClosure, websocket.CloseGoingAway):
+			h.log.Debug("WebSocket read error", slog.Any("error", err))
 		}
+	})
 
-		h.log.Debug("WebSocket Mux Request",
-			slog.String("json", string(bytes)),
-			slog.Any("struct", req),
+	h.wsHandler.HandleDisconnect(func(session *melody.Session) {
+		h.log.Debug("WebSocket Disconnected",
+			slog.String("remote_addr", session.RemoteAddr().String()),
 		)
+		if h.metrics != nil {
+			h.metrics.WsSessionClosed()
+		}
+		h.wsPresenceDisconnected(session)
+	})
 
-		gameID, err := h.WsGameIDFromSession(session)
-		if err != nil {
-			h.wsResponder.RespondErrorWs(session, err)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := restapi.PlayerIDFromContext(r.Context()); err != nil {
+			h.responder.RespondError(w, r, http.StatusUnauthorized, err)
 			return
 		}
 
-		if req.ClientID != "" {
-			session.Set("client_id", req.ClientID)
+		if !h.allowConnection(r) {
+			h.responder.RespondError(w, r, http.StatusTooManyRequests, restapi.ErrRateLimited)
+			return
 		}
 
-		switch req.Type {
-		case "presence":
-			h.WsPresence(session, req.RequestID, gameID, req.Message)
-		case "game":
-			h.WsGame(session, req.RequestID, gameID, req.Message)
-		case "state":
-			h.WsState(session, req.RequestID, gameID, req.Message)
-		case "side":
-			h.WsSide(session, req.RequestID, gameID, req.Message)
-		default:
-			h.WsRespondErrorWithID(session, ErrWrongMessageType, req.RequestID)
+		if !h.shutdown.acquire() {
+			h.responder.RespondError(w, r, http.StatusServiceUnavailable, restapi.ErrShuttingDown)
+			return
 		}
-	})
+		defer h.shutdown.release()
 
-	h.wsHandler.HandleDisconnect(func(session *melody.Session) {
-		h.log.Debug("WebSocket Disconnected",
-			slog.String("remote_addr", session.RemoteAddr().String()),
-		)
-	})
+		// the upgrader without subprotocols accepts the one of the response header
+		if subprotocol := h.wsSelectSubprotocol(r); subprotocol != "" {
+			w.Header().Set("Sec-WebSocket-Protocol", subprotocol)
+		}
 
-	return func(w http.ResponseWriter, r *http.Request) {
 		err := h.wsHandler.HandleRequest(w, r)
 		if err != nil {
 			return
@@ -147,6 +167,45 @@ func (h *Handler) WsMux() http.HandlerFunc {
 	}
 }
 
+func (h *Handler) wsHandleMessage(session *melody.Session, bytes []byte) {
+	if !h.wsAllowMessage(session) {
+		return
+	}
+
+	req := &WsMuxReq{}
+	if err := h.WsCodec(session).Unmarshal(bytes, req); err != nil {
+		h.WsCodec(session).RespondErrorWs(session, err)
+		return
+	}
+
+	h.log.Debug("WebSocket Mux Request",
+		slog.Any("struct", req),
+	)
+
+	gameID, err := h.WsGameIDFromSession(session)
+	if err != nil {
+		h.WsRespondErrorWithID(session, err, req.RequestID)
+		return
+	}
+
+	route, ok := h.WsRoute(session, req.Type)
+	if !ok {
+		h.WsRespondErrorWithID(session, ErrWrongMessageType, req.RequestID)
+		return
+	}
+
+	if err := h.WsCheckFeature(session, req.Type); err != nil {
+		h.WsRespondErrorWithID(session, err, req.RequestID)
+		return
+	}
+
+	// the changes of the game are broadcast before the next message of the game is handled
+	unlock := h.gameLocks.lock(gameID)
+	defer unlock()
+
+	route(session, req.RequestID, gameID, req.Message)
+}
+
 func (h *Handler) RespondErrorWsAndClose(session *melody.Session, requestID string, err error, log *slog.Logger) {
 	h.WsRespondErrorWithID(session, err, requestID)
 	closeErr := session.Close()
@@ -171,6 +230,75 @@ func (h *Handler) WsGameIDFromSession(session *melody.Session) (uuid.UUID, error
 	return gameID, nil
 }
 
+// WsBroadcastMeta is embedded into every broadcast.
+type WsBroadcastMeta struct {
+	// id of the request that has caused the broadcast
+	RequestID string `json:"request_id,omitempty"`
+	// per game sequence number of the broadcast, starting from 1
+	Seq int64 `json:"seq"`
+}
+
+func (m *WsBroadcastMeta) SetBroadcastMeta(meta WsBroadcastMeta) {
+	*m = meta
+}
+
+type WsBroadcast interface {
+	SetBroadcastMeta(meta WsBroadcastMeta)
+}
+
+type WsSuccessResp struct {
+	Type          string `json:"type"`
+	ResponseForID string `json:"response_for_id"`
+}
+
+var SuccessResponseType = "success_response"
+
+// WsBroadcastToGame sends the data to the game sessions and publishes it for SSE subscribers.
+// The data is encoded once for every codec used by the sessions.
+// The caller must hold the game lock, so the sessions get the broadcasts
+// in the order of their sequence numbers.
+func (h *Handler) WsBroadcastToGame(gameID uuid.UUID, requestID string, data WsBroadcast) {
+	h.events.PublishFunc(gameID, func(seq int64) interface{} {
+		data.SetBroadcastMeta(WsBroadcastMeta{RequestID: requestID, Seq: seq})
+		return data
+	})
+
+	sessions, err := h.wsHandler.Sessions()
+	if err != nil {
+		h.log.Error("ws game broadcast: get sessions", slog.Any("error", err))
+		return
+	}
+
+	encoded := make(map[restapi.WsCodec][]byte)
+	sent := 0
+
+	for _, session := range sessions {
+		otherGameID, err := h.WsGameIDFromSession(session)
+		if err != nil {
+			h.log.Error("game broadcast", slog.Any("error", err))
+			continue
+		}
+		if gameID != otherGameID {
+			continue
+		}
+
+		codec := h.WsCodec(session)
+
+		bytes, ok := encoded[codec]
+		if !ok {
+			bytes, _ = codec.Marshal(data)
+			encoded[codec] = bytes
+		}
+
+		codec.RespondWsBytes(session, bytes)
+		sent++
+	}
+
+	if h.metrics != nil {
+		h.metrics.ObserveBroadcast(sent)
+	}
+}
+
 func (h *Handler) WsGetSide(ctx context.Context, session *melody.Session, gameID uuid.UUID) (domain.Side, error) {
 	sideValue, ok := session.Get("side")
 	if !ok {
@@ -204,7 +332,7 @@ func (h *Handler) WsSendSide(ctx context.Context, session *melody.Session, reque
 		side = domain.NoneSide
 	}
 
-	h.wsResponder.RespondWs(session, WsSideResponse{
+	h.WsCodec(session).RespondWs(session, WsSideResponse{
 		Type:          SideMessageType,
 		Side:          side,
 		ResponseForID: requestID,
@@ -212,10 +340,7 @@ func (h *Handler) WsSendSide(ctx context.Context, session *melody.Session, reque
 }
 
 func (h *Handler) WsGetPlayerID(session *melody.Session) domain.PlayerID {
-	clientIDValue, ok := session.Get("client_id")
-	if !ok {
-		clientIDValue = session.Request.Header.Get("Client-Id")
-	}
+	clientIDValue, _ := session.Get("client_id")
 	clientID, _ := clientIDValue.(string)
 
 	return domain.PlayerID{
@@ -224,14 +349,29 @@ func (h *Handler) WsGetPlayerID(session *melody.Session) domain.PlayerID {
 }
 
 type WsError struct {
-	Error         string `json:"error"`
-	ResponseForID string `json:"response_for_id,omitempty"`
-	NeedReSync    bool   `json:"need_re_sync"`
+	// localized message
+	Error         string            `json:"error"`
+	Code          restapi.ErrorCode `json:"code"`
+	ResponseForID string            `json:"response_for_id,omitempty"`
+	NeedReSync    bool              `json:"need_re_sync"`
+}
+
+func (h *Handler) WsRespondSuccess(session *melody.Session, requestID string) {
+	h.WsCodec(session).RespondWs(session, WsSuccessResp{
+		Type:          SuccessResponseType,
+		ResponseForID: requestID,
+	})
 }
 
 func (h *Handler) WsRespondErrorWithID(session *melody.Session, err error, requestID string) {
-	h.wsResponder.RespondWs(session, &WsError{
-		Error:         err.Error(),
+	httpErr, status := h.errConverter.HTTPError(err, http.StatusBadRequest, session.Request.Header.Get("Accept-Language"))
+	if status >= http.StatusInternalServerError {
+		h.log.Error("ws: internal error", slog.Any("error", err))
+	}
+
+	h.WsCodec(session).RespondWs(session, &WsError{
+		Error:         httpErr.Error,
+		Code:          httpErr.Code,
 		ResponseForID: requestID,
 		NeedReSync:    domain.IsNeedReSync(err),
 	})
@@ -1,11 +1,13 @@
 package gamesrest
 
 import (
-	"encoding/json"
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
 	"errors"
 	"github.com/google/uuid"
 	"github.com/olahol/melody"
 	"log/slog"
+	"sync"
 )
 
 var (
@@ -18,22 +20,36 @@ type WsPresenceReq struct {
 }
 
 type WsGameStartBroadcast struct {
+	WsBroadcastMeta
+
 	Type string `json:"type"`
 }
 
-var GameStartBroadcastType = "start_broadcast"
+// WsPresenceBroadcast is sent when the player opens the first connection to the game
+// or loses the last one, including the connections that stopped answering pings.
+type WsPresenceBroadcast struct {
+	WsBroadcastMeta
+
+	Type     string `json:"type"`
+	ClientID string `json:"client_id"`
+	Online   bool   `json:"online"`
+}
+
+var (
+	GameStartBroadcastType = "start_broadcast"
+	PresenceBroadcastType  = "presence_broadcast"
+)
 
 func (h *Handler) WsPresence(session *melody.Session, requestID string, gameID uuid.UUID, bytes []byte) {
 	ctx := session.Request.Context()
 
 	req := &WsPresenceReq{}
-	if err := json.Unmarshal(bytes, req); err != nil {
+	if err := h.WsCodec(session).Unmarshal(bytes, req); err != nil {
 		h.WsRespondErrorWithID(session, err, requestID)
 		return
 	}
 
 	h.log.Debug("WebSocket Presence Request",
-		slog.String("json", string(bytes)),
 		slog.Any("struct", req),
 	)
 
@@ -61,26 +77,129 @@ func (h *Handler) WsPresence(session *melody.Session, requestID string, gameID u
 			return
 		}
 
+		// the side response is already the reply to the request
 		err = h.gameUC.StartGame(ctx, gameID)
 		if err != nil {
-			h.WsRespondErrorWithID(session, err, requestID)
+			h.log.Error("ws presence: start game", slog.Any("error", err))
 			return
 		}
 
-		data, _ := h.wsResponder.Marshal(WsGameStartBroadcast{Type: GameStartBroadcastType})
-
-		err = h.wsHandler.Broadcast(data)
-		if err != nil {
-			h.log.Error("ws handler broadcast", slog.Any("error", err))
-		}
+		h.WsBroadcastToGame(gameID, requestID, &WsGameStartBroadcast{Type: GameStartBroadcastType})
 	case "leave":
 		// todo
+		h.WsRespondErrorWithID(session, &PresenceActionError{
+			Err:    ErrPresenceActionNotImplemented,
+			Action: req.Action}, requestID)
 	default:
 		h.WsRespondErrorWithID(session, &PresenceActionError{
 			Err:    ErrInvalidPresenceAction,
 			Action: req.Action}, requestID)
+	}
+}
+
+// presence counts the WebSocket connections of the clients to the games.
+type presence struct {
+	conns map[uuid.UUID]map[string]int
+	mu    sync.Mutex
+}
+
+func newPresence() *presence {
+	return &presence{conns: make(map[uuid.UUID]map[string]int)}
+}
+
+// connect returns true for the first connection of the client to the game.
+func (p *presence) connect(gameID uuid.UUID, clientID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	clients, ok := p.conns[gameID]
+	if !ok {
+		clients = make(map[string]int)
+		p.conns[gameID] = clients
+	}
+
+	clients[clientID]++
+	return clients[clientID] == 1
+}
+
+// disconnect returns true for the last connection of the client to the game.
+func (p *presence) disconnect(gameID uuid.UUID, clientID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	clients := p.conns[gameID]
+	if clients[clientID] == 0 {
+		return false
+	}
+
+	clients[clientID]--
+	if clients[clientID] > 0 {
+		return false
+	}
+
+	delete(clients, clientID)
+	if len(clients) == 0 {
+		delete(p.conns, gameID)
+	}
+	return true
+}
+
+func (p *presence) isOnline(gameID uuid.UUID, clientID string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.conns[gameID][clientID] > 0
+}
+
+func (h *Handler) wsPresenceConnected(session *melody.Session, gameID uuid.UUID) {
+	playerID := h.WsGetPlayerID(session)
+	session.Set("presence", true)
+
+	if h.presence.connect(gameID, playerID.ClientID) {
+		h.broadcastPresence(session.Request.Context(), gameID, playerID, true)
+	}
+}
+
+func (h *Handler) wsPresenceDisconnected(session *melody.Session) {
+	// everyone is disconnected by the shutdown
+	if _, ok := session.Get("presence"); !ok || h.isShuttingDown() {
+		return
+	}
+
+	gameID, err := h.WsGameIDFromSession(session)
+	if err != nil {
+		return
+	}
+
+	playerID := h.WsGetPlayerID(session)
+	if h.presence.disconnect(gameID, playerID.ClientID) {
+		h.broadcastPresence(session.Request.Context(), gameID, playerID, false)
+	}
+}
+
+// broadcastPresence notifies the game about the players, spectators aren't announced.
+// Finished games don't get the broadcasts, their events are already removed.
+func (h *Handler) broadcastPresence(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID, online bool) {
+	unlock := h.gameLocks.lock(gameID)
+	defer unlock()
+
+	g, err := h.gameUC.GetGame(ctx, gameID)
+	if err != nil || g.State == domain.Finished || g.PlayerSide(playerID) == domain.NoneSide {
 		return
 	}
 
-	// todo
+	h.WsBroadcastToGame(gameID, "", &WsPresenceBroadcast{
+		Type:     PresenceBroadcastType,
+		ClientID: playerID.ClientID,
+		Online:   online,
+	})
+}
+
+// fillPresence marks the players having the connections to the game.
+func (h *Handler) fillPresence(gameID uuid.UUID, players GamePlayers) {
+	for _, player := range []*GamePlayer{players.X, players.O} {
+		if player != nil {
+			player.Online = h.presence.isOnline(gameID, player.ClientID)
+		}
+	}
 }
@@ -0,0 +1,70 @@
+package gamesrest
+
+import (
+	"bytes"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestPresence(t *testing.T) {
+	p := newPresence()
+	game, other := uuid.New(), uuid.New()
+
+	assert.True(t, p.connect(game, "a"), "first connection")
+	assert.False(t, p.connect(game, "a"), "second tab")
+	assert.True(t, p.connect(other, "a"), "other game")
+	assert.True(t, p.isOnline(game, "a"))
+	assert.False(t, p.isOnline(game, "b"))
+
+	assert.False(t, p.disconnect(game, "a"))
+	assert.True(t, p.isOnline(game, "a"))
+	assert.True(t, p.disconnect(game, "a"), "last connection")
+	assert.False(t, p.isOnline(game, "a"))
+	assert.False(t, p.disconnect(game, "a"), "already offline")
+	assert.True(t, p.isOnline(other, "a"))
+
+	assert.NotContains(t, p.conns, game, "empty games are removed")
+}
+
+func TestHandler_WsConfig(t *testing.T) {
+	cfg := WsConfig{MaxMessageSize: 1 << 10, WriteWait: time.Second, PongWait: 3 * time.Second, PingPeriod: 2 * time.Second}
+	h := New(nil, nil, nil, nil, WithWsConfig(cfg))
+
+	melodyCfg := h.wsHandler.Config
+	assert.Equal(t, cfg.MaxMessageSize, melodyCfg.MaxMessageSize)
+	assert.Equal(t, cfg.WriteWait, melodyCfg.WriteWait)
+	assert.Equal(t, cfg.PongWait, melodyCfg.PongWait)
+	assert.Equal(t, cfg.PingPeriod, melodyCfg.PingPeriod)
+
+	assert.Equal(t, DefaultWsConfig().MaxMessageSize, New(nil, nil, nil, nil).wsHandler.Config.MaxMessageSize)
+}
+
+func TestHandler_WsCheckOrigin(t *testing.T) {
+	logs := &bytes.Buffer{}
+	responder := restapi.NewJsonResponder(nil, nil)
+	policy := restapi.NewOriginPolicy("https://dataxo.example")
+	h := New(slog.New(slog.NewTextHandler(logs, nil)), spectatorGameUC{}, responder, responder,
+		WithCheckOrigin(policy.CheckOrigin))
+
+	server := newServer(h)
+	defer server.Close()
+
+	url := wsGameURL(server, uuid.New())
+
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {"https://dataxo.example"}})
+	require.NoError(t, err)
+	_ = conn.Close()
+	assert.Empty(t, logs.String())
+
+	_, resp, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {"https://evil.example"}})
+	require.Error(t, err)
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	assert.Contains(t, logs.String(), "origin=https://evil.example", "the rejection is logged")
+}
@@ -1,7 +1,6 @@
 package gamesrest
 
 import (
-	"encoding/json"
 	"github.com/olahol/melody"
 	"log/slog"
 )
@@ -13,13 +12,12 @@ type WsReadinessReq struct {
 func (h *Handler) WsReadiness(session *melody.Session, bytes []byte) {
 	// todo
 	req := &WsReadinessReq{}
-	if err := json.Unmarshal(bytes, req); err != nil {
-		h.wsResponder.RespondErrorWs(session, err)
+	if err := h.WsCodec(session).Unmarshal(bytes, req); err != nil {
+		h.WsCodec(session).RespondErrorWs(session, err)
 		return
 	}
 
 	h.log.Debug("WebSocket Readiness Request",
-		slog.String("json", string(bytes)),
 		slog.Any("struct", req),
 	)
 
@@ -29,7 +27,7 @@ func (h *Handler) WsReadiness(session *melody.Session, bytes []byte) {
 	case "unready":
 		// todo
 	default:
-		h.wsResponder.RespondErrorWs(session, &ReadinessError{
+		h.WsCodec(session).RespondErrorWs(session, &ReadinessError{
 			Err: ErrInvalidReadinessAction, Action: req.Action,
 		})
 		return
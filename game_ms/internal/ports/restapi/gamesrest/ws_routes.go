@@ -0,0 +1,118 @@
+package gamesrest
+
+import (
+	"github.com/google/uuid"
+	"github.com/olahol/melody"
+	"maps"
+	"slices"
+)
+
+type WsHandlerFunc func(session *melody.Session, requestID string, gameID uuid.UUID, bytes []byte)
+
+// WsRoute is a message type of the protocol version.
+type WsRoute struct {
+	Version int
+	Type    string
+}
+
+const (
+	ProtocolVersion1 = 1
+
+	// DefaultProtocolVersion is used by clients that don't send hello message.
+	DefaultProtocolVersion = ProtocolVersion1
+)
+
+// SupportedProtocolVersions are ordered from oldest to newest.
+var SupportedProtocolVersions = []int{ProtocolVersion1}
+
+// ProtocolFeatures are the optional capabilities of the protocol versions.
+var ProtocolFeatures = map[int][]string{
+	ProtocolVersion1: {"takeback", "draw", "resign", "sync"},
+}
+
+// MessageFeatures are the features the message types need, other types are always routed.
+var MessageFeatures = map[WsRoute]string{
+	{Version: ProtocolVersion1, Type: "takeback"}:     "takeback",
+	{Version: ProtocolVersion1, Type: "offer_draw"}:   "draw",
+	{Version: ProtocolVersion1, Type: "accept_draw"}:  "draw",
+	{Version: ProtocolVersion1, Type: "decline_draw"}: "draw",
+	{Version: ProtocolVersion1, Type: "resign"}:       "resign",
+	{Version: ProtocolVersion1, Type: "sync"}:         "sync",
+}
+
+func (h *Handler) setupWsRoutes() {
+	v1 := map[string]WsHandlerFunc{
+		"hello":        h.WsHello,
+		"presence":     h.WsPresence,
+		"game":         h.WsGame,
+		"state":        h.WsState,
+		"sync":         h.WsSync,
+		"side":         h.WsSide,
+		"takeback":     h.WsTakeback,
+		"offer_draw":   h.WsOfferDraw,
+		"accept_draw":  h.WsAcceptDraw,
+		"decline_draw": h.WsDeclineDraw,
+		"resign":       h.WsResign,
+	}
+
+	h.wsRoutes = make(map[WsRoute]WsHandlerFunc)
+	for t, fn := range v1 {
+		h.wsRoutes[WsRoute{Version: ProtocolVersion1, Type: t}] = fn
+	}
+}
+
+// WsRoutes returns the registered message types of all protocol versions.
+func (h *Handler) WsRoutes() []WsRoute {
+	return slices.Collect(maps.Keys(h.wsRoutes))
+}
+
+func (h *Handler) WsRoute(session *melody.Session, msgType string) (WsHandlerFunc, bool) {
+	fn, ok := h.wsRoutes[WsRoute{Version: h.WsProtocolVersion(session), Type: msgType}]
+	return fn, ok
+}
+
+// WsCheckFeature returns the error if the message type needs the feature
+// that the session hasn't negotiated.
+func (h *Handler) WsCheckFeature(session *melody.Session, msgType string) error {
+	version := h.WsProtocolVersion(session)
+	feature, ok := MessageFeatures[WsRoute{Version: version, Type: msgType}]
+	if !ok {
+		return nil
+	}
+
+	// clients without hello message have all features of the default version
+	features := ProtocolFeatures[version]
+	if featuresValue, ok := session.Get("features"); ok {
+		features, _ = featuresValue.([]string)
+	}
+
+	if !slices.Contains(features, feature) {
+		return &FeatureError{Err: ErrFeatureNotNegotiated, Feature: feature}
+	}
+	return nil
+}
+
+func (h *Handler) WsProtocolVersion(session *melody.Session) int {
+	versionValue, ok := session.Get("protocol_version")
+	if !ok {
+		return DefaultProtocolVersion
+	}
+
+	version, ok := versionValue.(int)
+	if !ok {
+		return DefaultProtocolVersion
+	}
+
+	return version
+}
+
+// NegotiateProtocolVersion returns the newest supported version
+// that is not newer than the client version.
+func NegotiateProtocolVersion(clientVersion int) (int, bool) {
+	for _, version := range slices.Backward(SupportedProtocolVersions) {
+		if version <= clientVersion {
+			return version, true
+		}
+	}
+	return 0, false
+}
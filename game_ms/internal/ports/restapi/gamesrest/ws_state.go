@@ -1,6 +1,7 @@
 package gamesrest
 
 import (
+	"context"
 	"dataxo-backend-game-ms/internal/domain"
 	"github.com/google/uuid"
 	"github.com/olahol/melody"
@@ -15,17 +16,103 @@ type WsGameConfig struct {
 	BoardHeight        int `json:"board_height"`
 }
 
-type WsGameStateResp struct {
-	Type          string `json:"type"`
-	ResponseForID string `json:"response_for_id"`
+type GamePlayer struct {
+	ClientID string `json:"client_id"`
+	Ready    bool   `json:"ready"`
+	// the player has the WebSocket connection to the game
+	Online bool `json:"online"`
+}
+
+type GamePlayers struct {
+	X *GamePlayer `json:"x"`
+	O *GamePlayer `json:"o"`
+}
+
+func GamePlayerFromDomain(player *domain.Player) *GamePlayer {
+	if player == nil {
+		return nil
+	}
+
+	return &GamePlayer{ClientID: player.ID.ClientID, Ready: player.Ready}
+}
 
+type GameResp struct {
 	GameID      uuid.UUID      `json:"game_id"`
 	Mode        string         `json:"mode"`
 	Config      WsGameConfig   `json:"config"`
 	State       string         `json:"state"`
+	Players     GamePlayers    `json:"players"`
 	Moves       []domain.Move  `json:"moves"`
 	WinSequence []domain.Move  `json:"win_sequence"`
 	Winner      domain.WinSide `json:"winner"`
+	Reason      string         `json:"reason"`
+	Rated       bool           `json:"rated"`
+	Imported    bool           `json:"imported"`
+	SeriesID    string         `json:"series_id,omitempty"`
+	Series      *SeriesResp    `json:"series,omitempty"`
+}
+
+func (r *GameResp) FromDomain(g *domain.Game) {
+	cfg := g.Config
+
+	r.GameID = g.ID
+	r.Mode = g.Mode
+	r.Config = WsGameConfig{
+		PlayerFiguresLimit: cfg.PlayerFiguresLimit,
+		WinLineLength:      cfg.WinLineLength,
+		BoardWidth:         cfg.BoardWidth,
+		BoardHeight:        cfg.BoardHeight,
+	}
+	r.State = g.State.String()
+	r.Players = GamePlayers{
+		X: GamePlayerFromDomain(g.XPlayer),
+		O: GamePlayerFromDomain(g.OPlayer),
+	}
+	r.Moves = g.Moves
+	r.WinSequence = g.WinSequence
+	r.Winner = g.Winner
+	r.Reason = g.FinishReason.String()
+	r.Rated = g.Rated
+	r.Imported = g.Imported
+
+	if g.SeriesID != uuid.Nil {
+		r.SeriesID = g.SeriesID.String()
+	}
+}
+
+// GetGameResp returns the game with its series.
+func (h *Handler) GetGameResp(ctx context.Context, gameID uuid.UUID) (GameResp, error) {
+	g, err := h.gameUC.GetGame(ctx, gameID)
+	if err != nil {
+		return GameResp{}, err
+	}
+
+	resp := GameResp{}
+	resp.FromDomain(g)
+	h.fillPresence(gameID, resp.Players)
+
+	if g.SeriesID == uuid.Nil {
+		return resp, nil
+	}
+
+	s, err := h.gameUC.GetSeries(ctx, g.SeriesID)
+	if err != nil {
+		return GameResp{}, err
+	}
+
+	resp.Series = &SeriesResp{}
+	resp.Series.FromDomain(s)
+
+	return resp, nil
+}
+
+type WsGameStateResp struct {
+	Type          string `json:"type"`
+	ResponseForID string `json:"response_for_id"`
+	// sequence number of the last broadcast included in the state
+	Seq int64 `json:"seq"`
+
+	GameResp
 }
 
 var GameStateResponseType = "game_state_response"
@@ -33,29 +120,19 @@ var GameStateResponseType = "game_state_response"
 func (h *Handler) WsState(session *melody.Session, requestID string, gameID uuid.UUID, bytes []byte) {
 	ctx := session.Request.Context()
 
-	g, err := h.gameUC.GetGame(ctx, gameID)
+	resp, err := h.GetGameResp(ctx, gameID)
 	if err != nil {
 		h.log.Error("ws state: get game", slog.Any("error", err))
 		h.WsRespondErrorWithID(session, err, requestID)
 		return
 	}
+	// the game lock of the message keeps the sequence number in line with the state
+	seq := h.events.LastID(gameID)
 
-	cfg := g.Config
-
-	h.wsResponder.RespondWs(session, WsGameStateResp{
+	h.WsCodec(session).RespondWs(session, WsGameStateResp{
 		Type:          GameStateResponseType,
 		ResponseForID: requestID,
-		GameID:        gameID,
-		Mode:          g.Mode,
-		Config: WsGameConfig{
-			PlayerFiguresLimit: cfg.PlayerFiguresLimit,
-			WinLineLength:      cfg.WinLineLength,
-			BoardWidth:         cfg.BoardWidth,
-			BoardHeight:        cfg.BoardHeight,
-		},
-		State:       g.State.String(),
-		Moves:       g.Moves,
-		WinSequence: g.WinSequence,
-		Winner:      g.Winner,
+		Seq:           seq,
+		GameResp:      resp,
 	})
 }
@@ -0,0 +1,67 @@
+package gamesrest
+
+import (
+	"github.com/google/uuid"
+	"github.com/olahol/melody"
+	"log/slog"
+)
+
+type WsSyncReq struct {
+	// sequence number of the last broadcast the client has got
+	LastSeq int64 `json:"last_seq"`
+}
+
+// WsSyncResp contains either the missed broadcasts or the full game state
+// if some of them aren't available anymore.
+type WsSyncResp struct {
+	Type          string `json:"type"`
+	ResponseForID string `json:"response_for_id"`
+	// sequence number the client is synced up to
+	Seq    int64         `json:"seq"`
+	Events []interface{} `json:"events"`
+	State  *GameResp     `json:"state,omitempty"`
+}
+
+var SyncResponseType = "sync_response"
+
+func (h *Handler) WsSync(session *melody.Session, requestID string, gameID uuid.UUID, bytes []byte) {
+	ctx := session.Request.Context()
+
+	req := &WsSyncReq{}
+	if err := h.WsCodec(session).Unmarshal(bytes, req); err != nil {
+		h.WsRespondErrorWithID(session, err, requestID)
+		return
+	}
+
+	// the client is ahead of the server after its restart or the topic removal,
+	// the events aren't complete then
+	events, seq, complete := h.events.SinceLastID(gameID, req.LastSeq)
+
+	resp := WsSyncResp{
+		Type:          SyncResponseType,
+		ResponseForID: requestID,
+		Seq:           seq,
+	}
+
+	if complete {
+		resp.Seq = req.LastSeq
+		resp.Events = make([]interface{}, 0, len(events))
+		for _, event := range events {
+			resp.Events = append(resp.Events, event.Data)
+			resp.Seq = event.ID
+		}
+
+		h.WsCodec(session).RespondWs(session, resp)
+		return
+	}
+
+	state, err := h.GetGameResp(ctx, gameID)
+	if err != nil {
+		h.log.Error("ws sync: get game", slog.Any("error", err))
+		h.WsRespondErrorWithID(session, err, requestID)
+		return
+	}
+	resp.State = &state
+
+	h.WsCodec(session).RespondWs(session, resp)
+}
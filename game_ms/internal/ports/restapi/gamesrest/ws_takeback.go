@@ -0,0 +1,99 @@
+package gamesrest
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"github.com/google/uuid"
+	"github.com/olahol/melody"
+	"log/slog"
+)
+
+type WsTakebackReq struct {
+	Action string `json:"action"`
+}
+
+type WsTakebackProposalBroadcast struct {
+	WsBroadcastMeta
+
+	Type string      `json:"type"`
+	Side domain.Side `json:"side"`
+}
+
+type WsTakebackDeclineBroadcast struct {
+	WsBroadcastMeta
+
+	Type string `json:"type"`
+}
+
+type WsTakebackBroadcast struct {
+	WsBroadcastMeta
+
+	Type       string      `json:"type"`
+	MoveEvents []MoveEvent `json:"move_events"`
+}
+
+var (
+	TakebackProposalBroadcastType = "takeback_proposal_broadcast"
+	TakebackDeclineBroadcastType  = "takeback_decline_broadcast"
+	TakebackBroadcastType         = "takeback_broadcast"
+)
+
+func (h *Handler) WsTakeback(session *melody.Session, requestID string, gameID uuid.UUID, bytes []byte) {
+	ctx := session.Request.Context()
+
+	req := &WsTakebackReq{}
+	if err := h.WsCodec(session).Unmarshal(bytes, req); err != nil {
+		h.WsRespondErrorWithID(session, err, requestID)
+		return
+	}
+
+	h.log.Debug("WebSocket Takeback Request",
+		slog.Any("struct", req),
+	)
+
+	playerID := h.WsGetPlayerID(session)
+
+	switch req.Action {
+	case "propose":
+		side, err := h.gameUC.ProposeTakeback(ctx, gameID, playerID)
+		if err != nil {
+			h.WsRespondErrorWithID(session, err, requestID)
+			return
+		}
+
+		h.WsRespondSuccess(session, requestID)
+
+		h.WsBroadcastToGame(gameID, requestID, &WsTakebackProposalBroadcast{
+			Type: TakebackProposalBroadcastType,
+			Side: side,
+		})
+	case "accept":
+		res, err := h.gameUC.AnswerTakeback(ctx, gameID, playerID, true)
+		if err != nil {
+			h.WsRespondErrorWithID(session, err, requestID)
+			return
+		}
+
+		h.WsRespondSuccess(session, requestID)
+
+		h.WsBroadcastToGame(gameID, requestID, &WsTakebackBroadcast{
+			Type:       TakebackBroadcastType,
+			MoveEvents: MoveEventsFromDomain(res.Events),
+		})
+	case "decline":
+		_, err := h.gameUC.AnswerTakeback(ctx, gameID, playerID, false)
+		if err != nil {
+			h.WsRespondErrorWithID(session, err, requestID)
+			return
+		}
+
+		h.WsRespondSuccess(session, requestID)
+
+		h.WsBroadcastToGame(gameID, requestID, &WsTakebackDeclineBroadcast{
+			Type: TakebackDeclineBroadcastType,
+		})
+	default:
+		h.WsRespondErrorWithID(session, &TakebackActionError{
+			Err:    ErrInvalidTakebackAction,
+			Action: req.Action}, requestID)
+	}
+}
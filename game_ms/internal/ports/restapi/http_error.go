@@ -1,5 +1,7 @@
 package restapi
 
 type HTTPError struct {
-	Error string `json:"error"`
+	// localized message
+	Error string    `json:"error"`
+	Code  ErrorCode `json:"code"`
 }
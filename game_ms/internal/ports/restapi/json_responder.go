@@ -9,13 +9,18 @@ import (
 )
 
 type JsonResponder struct {
-	log *slog.Logger
+	log          *slog.Logger
+	errConverter *ErrConverter
 }
 
-func NewJsonResponder(log *slog.Logger) *JsonResponder {
+// NewJsonResponder creates the responder, nil errConverter converts only the domain and auth errors.
+func NewJsonResponder(log *slog.Logger, errConverter *ErrConverter) *JsonResponder {
 	log = slogdiscard.LoggerIfNil(log)
+	if errConverter == nil {
+		errConverter = NewErrConverter()
+	}
 
-	return &JsonResponder{log: log}
+	return &JsonResponder{log: log, errConverter: errConverter}
 }
 
 func (r *JsonResponder) Marshal(data interface{}) ([]byte, error) {
@@ -33,13 +38,17 @@ func (r *JsonResponder) Marshal(data interface{}) ([]byte, error) {
 	if err != nil {
 		log.Error("respond helper: json marshal error", slog.Any("error", err))
 
-		jsonBytes = []byte("{\"error\": \"json marshal error\"}")
+		jsonBytes = []byte("{\"error\": \"json marshal error\", \"code\": \"internal\"}")
 		return jsonBytes, err
 	}
 
 	return jsonBytes, nil
 }
 
+func (r *JsonResponder) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
 func (r *JsonResponder) Respond(w http.ResponseWriter, code int, data interface{}) {
 	log := r.log
 
@@ -59,8 +68,13 @@ func (r *JsonResponder) Respond(w http.ResponseWriter, code int, data interface{
 	}
 }
 
-func (r *JsonResponder) RespondError(w http.ResponseWriter, code int, err error) {
-	r.Respond(w, code, &HTTPError{Error: err.Error()})
+func (r *JsonResponder) RespondError(w http.ResponseWriter, req *http.Request, code int, err error) {
+	httpErr, code := r.errConverter.HTTPError(err, code, req.Header.Get("Accept-Language"))
+	if code >= http.StatusInternalServerError {
+		r.log.Error("respond helper: internal error", slog.Any("error", err))
+	}
+
+	r.Respond(w, code, httpErr)
 }
 
 func (r *JsonResponder) RespondWs(session *melody.Session, data interface{}) {
@@ -76,5 +90,6 @@ func (r *JsonResponder) RespondWsBytes(session *melody.Session, b []byte) {
 }
 
 func (r *JsonResponder) RespondErrorWs(session *melody.Session, err error) {
-	r.RespondWs(session, &HTTPError{Error: err.Error()})
+	httpErr, _ := r.errConverter.HTTPError(err, http.StatusBadRequest, session.Request.Header.Get("Accept-Language"))
+	r.RespondWs(session, httpErr)
 }
@@ -2,33 +2,62 @@ package restapi
 
 import (
 	"dataxo-backend-game-ms/pkg/httplog"
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"log/slog"
 	"net/http"
 	"time"
 )
 
+// RequestObserver records the completed requests, e.g. as metrics.
+type RequestObserver interface {
+	// route is the pattern of the matched route, empty if no route is matched
+	ObserveRequest(method, route string, status int, elapsed time.Duration)
+}
+
 // LogFormatter to implement middlewares.LogFormatter
 type LogFormatter struct {
 	log          *slog.Logger
 	defaultLevel slog.Level
+	// nil if the requests aren't observed
+	observer RequestObserver
 }
 
-func NewLogFormatter(log *slog.Logger, defaultLevel slog.Level) *LogFormatter {
-	return &LogFormatter{
+type LogFormatterOpt func(l *LogFormatter)
+
+func WithRequestObserver(observer RequestObserver) LogFormatterOpt {
+	return func(l *LogFormatter) {
+		l.observer = observer
+	}
+}
+
+func NewLogFormatter(log *slog.Logger, defaultLevel slog.Level, opts ...LogFormatterOpt) *LogFormatter {
+	l := &LogFormatter{
 		log:          log.With(slog.String("context", "rest logging middleware")),
 		defaultLevel: defaultLevel,
 	}
+
+	for _, opt := range opts {
+		opt(l)
+	}
+
+	return l
 }
 
 func (l *LogFormatter) NewLogEntry(r *http.Request) middleware.LogEntry {
-	return NewLogEntry(l.log, httplog.New(l.log, l.defaultLevel), r)
+	reqLog := httplog.New(l.log, l.defaultLevel)
+	reqLog.RedactedParams = []string{TokenQueryParam}
+
+	entry := NewLogEntry(l.log, reqLog, r)
+	entry.observer = l.observer
+	return entry
 }
 
 // LogEntry to implement middlewares.LogEntry
 type LogEntry struct {
-	log    *slog.Logger
-	reqLog *httplog.RequestLogger
+	log      *slog.Logger
+	reqLog   *httplog.RequestLogger
+	observer RequestObserver
 
 	r *http.Request
 }
@@ -43,6 +72,25 @@ func (e *LogEntry) Write(status, bytes int, _ http.Header, elapsed time.Duration
 	ctx := e.r.Context()
 
 	e.reqLog.LogEnd(ctx, middleware.GetReqID(ctx), status, elapsed)
+
+	if e.observer == nil {
+		return
+	}
+
+	// the status isn't known if the connection is hijacked or nothing is written
+	if status == 0 {
+		status = http.StatusOK
+		if e.r.Header.Get("Upgrade") != "" {
+			status = http.StatusSwitchingProtocols
+		}
+	}
+
+	var route string
+	if rctx := chi.RouteContext(ctx); rctx != nil {
+		route = rctx.RoutePattern()
+	}
+
+	e.observer.ObserveRequest(e.r.Method, route, status, elapsed)
 }
 
 func (e *LogEntry) Panic(v interface{}, stack []byte) {
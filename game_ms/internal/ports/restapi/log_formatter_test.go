@@ -0,0 +1,45 @@
+package restapi
+
+import (
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/stretchr/testify/assert"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type observedRequest struct {
+	method, route string
+	status        int
+}
+
+type requestRecorder []observedRequest
+
+func (r *requestRecorder) ObserveRequest(method, route string, status int, elapsed time.Duration) {
+	*r = append(*r, observedRequest{method: method, route: route, status: status})
+}
+
+func TestLogFormatter_RequestObserver(t *testing.T) {
+	observed := &requestRecorder{}
+
+	router := chi.NewRouter()
+	router.Use(middleware.RequestLogger(NewLogFormatter(slog.New(slog.DiscardHandler), slog.LevelInfo,
+		WithRequestObserver(observed))))
+	router.Get("/games/{game_id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	router.Get("/empty", func(w http.ResponseWriter, r *http.Request) {})
+
+	for _, path := range []string{"/games/42", "/empty", "/unknown"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	assert.Equal(t, requestRecorder{
+		{method: http.MethodGet, route: "/games/{game_id}", status: http.StatusNotFound},
+		{method: http.MethodGet, route: "/empty", status: http.StatusOK},
+		{method: http.MethodGet, route: "", status: http.StatusNotFound},
+	}, *observed)
+}
@@ -0,0 +1,145 @@
+package restapi
+
+// Messages are the error messages by language and code.
+var Messages = map[string]map[ErrorCode]string{
+	"en": {
+		CodeInternal:       "Internal server error",
+		CodeBadRequest:     "Bad request",
+		CodeUnauthorized:   "Unauthorized",
+		CodeForbidden:      "Forbidden",
+		CodeNotFound:       "Not found",
+		CodeConflict:       "Conflict",
+		CodeUnprocessable:  "Request can't be processed",
+		CodeNotImplemented: "Not implemented yet",
+
+		CodeInvalidSide:    "Invalid side",
+		CodeInvalidWinSide: "Invalid winner",
+		CodeInvalidState:   "Invalid game state",
+		CodeInvalidPly:     "There is no such move in the game",
+		CodeInvalidConfig:  "Invalid game settings",
+
+		CodeGameNotStarted:     "The game hasn't started yet",
+		CodeGameAlreadyStarted: "The game has already started",
+		CodeGameFinished:       "The game is finished",
+
+		CodePlaceAlreadyTaken: "The place is already taken",
+		CodeMoveOutOfBoard:    "The move is out of the board",
+		CodeInvalidMoveID:     "The move is out of date",
+		CodeNotYourTurn:       "It's not your turn",
+
+		CodeUnsupportedMode:    "The game mode isn't supported",
+		CodeUnsupportedConfig:  "The game settings aren't supported by the game mode",
+		CodeMovesAfterFinish:   "There are moves after the game finish",
+		CodeGameResultMismatch: "The game result doesn't match the moves",
+		CodeMissingPlayers:     "Both players must be specified",
+
+		CodeNoMoveToTakeback:        "There is no move to take back",
+		CodeLastMoveIsNotYours:      "The last move isn't yours",
+		CodeTakebackAlreadyProposed: "A takeback is already proposed",
+		CodeTakebackNotProposed:     "There is no takeback proposal",
+		CodeCantAnswerOwnTakeback:   "You can't answer your own takeback proposal",
+		CodeDrawAlreadyOffered:      "A draw is already offered",
+		CodeDrawNotOffered:          "There is no draw offer",
+		CodeCantAnswerOwnDrawOffer:  "You can't answer your own draw offer",
+		CodePlayerIsNotInGame:       "You aren't a player of the game",
+
+		CodeAlreadyJoined:         "You have already joined the game",
+		CodeAllPlacesAlreadyTaken: "All places in the game are already taken",
+		CodeNotEnoughPlayers:      "Not enough players",
+
+		CodeInvalidSeriesBestOf: "Series length must be an odd number from 1 to 7",
+		CodeSeriesFinished:      "The series is finished",
+
+		CodeInvalidUsername:    "Username must be 3-32 latin letters, digits or underscores",
+		CodeWeakPassword:       "Password must be 8-72 characters long, non-Latin characters count as 2-4",
+		CodeInvalidDisplayName: "Name must be 1-64 characters long",
+		CodeUsernameTaken:      "The username is already taken",
+		CodeInvalidCredentials: "Invalid username or password",
+		CodeAlreadyRegistered:  "You are already registered",
+
+		CodeInvalidLeaderboard: "Unknown leaderboard",
+		CodeMissingRatingPool:  "Choose the game mode and board of the leaderboard",
+
+		CodeWrongMessageType:           "Unknown message type",
+		CodeUnsupportedProtocolVersion: "The protocol version isn't supported",
+		CodeFeatureNotNegotiated:       "The feature wasn't enabled in the hello message",
+		CodeInvalidAction:              "Unknown action",
+		CodeInvalidPagination:          "Invalid pagination parameters",
+		CodeMissingToken:               "Sign in to continue",
+		CodeInvalidToken:               "Your session is invalid or expired, sign in again",
+		CodeOriginNotAllowed:           "The site isn't allowed to use the game server",
+		CodeRateLimited:                "Too many requests, slow down",
+		CodeShuttingDown:               "The server is restarting, reconnect in a few seconds",
+		CodeNotAPlayer:                 "You aren't a player of the game",
+	},
+	"ru": {
+		CodeInternal:       "Внутренняя ошибка сервера",
+		CodeBadRequest:     "Некорректный запрос",
+		CodeUnauthorized:   "Требуется авторизация",
+		CodeForbidden:      "Доступ запрещён",
+		CodeNotFound:       "Не найдено",
+		CodeConflict:       "Конфликт",
+		CodeUnprocessable:  "Запрос не может быть обработан",
+		CodeNotImplemented: "Пока не реализовано",
+
+		CodeInvalidSide:    "Неверная сторона",
+		CodeInvalidWinSide: "Неверный победитель",
+		CodeInvalidState:   "Неверное состояние игры",
+		CodeInvalidPly:     "В игре нет такого хода",
+		CodeInvalidConfig:  "Неверные настройки игры",
+
+		CodeGameNotStarted:     "Игра ещё не началась",
+		CodeGameAlreadyStarted: "Игра уже началась",
+		CodeGameFinished:       "Игра завершена",
+
+		CodePlaceAlreadyTaken: "Клетка уже занята",
+		CodeMoveOutOfBoard:    "Ход за пределами поля",
+		CodeInvalidMoveID:     "Ход устарел",
+		CodeNotYourTurn:       "Сейчас не ваш ход",
+
+		CodeUnsupportedMode:    "Такой режим игры не поддерживается",
+		CodeUnsupportedConfig:  "Режим игры не поддерживает такие настройки",
+		CodeMovesAfterFinish:   "После завершения игры есть ходы",
+		CodeGameResultMismatch: "Результат игры не соответствует ходам",
+		CodeMissingPlayers:     "Нужно указать обоих игроков",
+
+		CodeNoMoveToTakeback:        "Нет хода для отмены",
+		CodeLastMoveIsNotYours:      "Последний ход сделан не вами",
+		CodeTakebackAlreadyProposed: "Отмена хода уже предложена",
+		CodeTakebackNotProposed:     "Отмена хода не предлагалась",
+		CodeCantAnswerOwnTakeback:   "Нельзя ответить на своё предложение отменить ход",
+		CodeDrawAlreadyOffered:      "Ничья уже предложена",
+		CodeDrawNotOffered:          "Ничья не предлагалась",
+		CodeCantAnswerOwnDrawOffer:  "Нельзя ответить на своё предложение ничьей",
+		CodePlayerIsNotInGame:       "Вы не участвуете в этой игре",
+
+		CodeAlreadyJoined:         "Вы уже присоединились к игре",
+		CodeAllPlacesAlreadyTaken: "Все места в игре заняты",
+		CodeNotEnoughPlayers:      "Недостаточно игроков",
+
+		CodeInvalidSeriesBestOf: "Длина серии должна быть нечётным числом от 1 до 7",
+		CodeSeriesFinished:      "Серия завершена",
+
+		CodeInvalidUsername:    "Имя пользователя должно состоять из 3-32 латинских букв, цифр или подчёркиваний",
+		CodeWeakPassword:       "Пароль должен быть длиной от 8 до 72 символов, нелатинские символы считаются за 2-4",
+		CodeInvalidDisplayName: "Имя должно быть длиной от 1 до 64 символов",
+		CodeUsernameTaken:      "Имя пользователя уже занято",
+		CodeInvalidCredentials: "Неверное имя пользователя или пароль",
+		CodeAlreadyRegistered:  "Вы уже зарегистрированы",
+
+		CodeInvalidLeaderboard: "Неизвестная таблица лидеров",
+		CodeMissingRatingPool:  "Выберите режим игры и доску таблицы лидеров",
+
+		CodeWrongMessageType:           "Неизвестный тип сообщения",
+		CodeUnsupportedProtocolVersion: "Версия протокола не поддерживается",
+		CodeFeatureNotNegotiated:       "Функция не была включена в сообщении hello",
+		CodeInvalidAction:              "Неизвестное действие",
+		CodeInvalidPagination:          "Неверные параметры пагинации",
+		CodeMissingToken:               "Войдите, чтобы продолжить",
+		CodeInvalidToken:               "Сессия недействительна или истекла, войдите снова",
+		CodeOriginNotAllowed:           "Этому сайту не разрешено использовать игровой сервер",
+		CodeRateLimited:                "Слишком много запросов, подождите немного",
+		CodeShuttingDown:               "Сервер перезапускается, переподключитесь через несколько секунд",
+		CodeNotAPlayer:                 "Вы не участвуете в этой игре",
+	},
+}
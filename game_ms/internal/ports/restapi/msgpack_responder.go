@@ -0,0 +1,69 @@
+package restapi
+
+import (
+	"bytes"
+	"dataxo-backend-game-ms/pkg/slogdiscard"
+	"github.com/olahol/melody"
+	"github.com/vmihailenco/msgpack/v5"
+	"log/slog"
+	"net/http"
+)
+
+// MsgpackResponder encodes WebSocket messages as MessagePack binary messages.
+// Struct fields use the same names as in JSON.
+type MsgpackResponder struct {
+	log          *slog.Logger
+	errConverter *ErrConverter
+}
+
+// NewMsgpackResponder creates the responder, nil errConverter converts only the domain and auth errors.
+func NewMsgpackResponder(log *slog.Logger, errConverter *ErrConverter) *MsgpackResponder {
+	log = slogdiscard.LoggerIfNil(log)
+	if errConverter == nil {
+		errConverter = NewErrConverter()
+	}
+
+	return &MsgpackResponder{log: log, errConverter: errConverter}
+}
+
+func (r *MsgpackResponder) Marshal(data interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+
+	enc := msgpack.NewEncoder(buf)
+	enc.SetCustomStructTag("json")
+
+	err := enc.Encode(data)
+	if err != nil {
+		r.log.Error("msgpack respond helper: marshal error", slog.Any("error", err))
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (r *MsgpackResponder) Unmarshal(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+
+	return dec.Decode(v)
+}
+
+func (r *MsgpackResponder) RespondWs(session *melody.Session, data interface{}) {
+	b, err := r.Marshal(data)
+	if err != nil {
+		b, _ = r.Marshal(&HTTPError{Error: "msgpack marshal error", Code: CodeInternal})
+	}
+	r.RespondWsBytes(session, b)
+}
+
+func (r *MsgpackResponder) RespondWsBytes(session *melody.Session, b []byte) {
+	err := session.WriteBinary(b)
+	if err != nil {
+		r.log.Error("ws respond helper: response write msgpack data error", slog.Any("error", err))
+	}
+}
+
+func (r *MsgpackResponder) RespondErrorWs(session *melody.Session, err error) {
+	httpErr, _ := r.errConverter.HTTPError(err, http.StatusBadRequest, session.Request.Header.Get("Accept-Language"))
+	r.RespondWs(session, httpErr)
+}
@@ -0,0 +1,32 @@
+package restapi
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+)
+
+var ErrInvalidPagination = errors.New("invalid pagination params")
+
+// ParsePagination parses limit and offset query params,
+// the limit is defaultLimit if it isn't set.
+func ParsePagination(r *http.Request, defaultLimit, maxLimit int) (limit, offset int, err error) {
+	query := r.URL.Query()
+
+	limit = defaultLimit
+	if limitParam := query.Get("limit"); limitParam != "" {
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit <= 0 || limit > maxLimit {
+			return 0, 0, ErrInvalidPagination
+		}
+	}
+
+	if offsetParam := query.Get("offset"); offsetParam != "" {
+		offset, err = strconv.Atoi(offsetParam)
+		if err != nil || offset < 0 {
+			return 0, 0, ErrInvalidPagination
+		}
+	}
+
+	return limit, offset, nil
+}
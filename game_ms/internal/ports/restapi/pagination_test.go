@@ -0,0 +1,43 @@
+package restapi
+
+import (
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParsePagination(t *testing.T) {
+	tcases := []struct {
+		Name   string
+		Query  string
+		Limit  int
+		Offset int
+		Err    error
+	}{
+		{Name: "defaults", Query: "", Limit: 20, Offset: 0},
+		{Name: "limit and offset", Query: "limit=5&offset=10", Limit: 5, Offset: 10},
+		{Name: "max limit", Query: "limit=100", Limit: 100},
+		{Name: "zero offset", Query: "offset=0", Limit: 20},
+		{Name: "zero limit", Query: "limit=0", Err: ErrInvalidPagination},
+		{Name: "negative limit", Query: "limit=-1", Err: ErrInvalidPagination},
+		{Name: "limit over max", Query: "limit=101", Err: ErrInvalidPagination},
+		{Name: "non-numeric limit", Query: "limit=ten", Err: ErrInvalidPagination},
+		{Name: "negative offset", Query: "offset=-5", Err: ErrInvalidPagination},
+		{Name: "non-numeric offset", Query: "offset=1.5", Err: ErrInvalidPagination},
+	}
+
+	for _, tc := range tcases {
+		r := httptest.NewRequest(http.MethodGet, "/?"+tc.Query, nil)
+
+		limit, offset, err := ParsePagination(r, 20, 100)
+		if tc.Err != nil {
+			assert.ErrorIs(t, err, tc.Err, tc.Name)
+			continue
+		}
+
+		assert.NoError(t, err, tc.Name)
+		assert.Equal(t, tc.Limit, limit, tc.Name)
+		assert.Equal(t, tc.Offset, offset, tc.Name)
+	}
+}
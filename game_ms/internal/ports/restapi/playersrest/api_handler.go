@@ -0,0 +1,43 @@
+package playersrest
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"dataxo-backend-game-ms/pkg/slogdiscard"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+type RatingUsecase interface {
+	GetRatings(ctx context.Context, playerID domain.PlayerID) ([]domain.Rating, error)
+	GetLeaderboard(ctx context.Context, pool string, leaderboard domain.Leaderboard, limit, offset int) ([]domain.LeaderboardEntry, error)
+}
+
+type GameUsecase interface {
+	ListGames(ctx context.Context, filter domain.GameFilter) ([]*domain.Game, int, error)
+	GetPlayerStats(ctx context.Context, playerID domain.PlayerID) (domain.PlayerStats, error)
+}
+
+type Handler struct {
+	log       *slog.Logger
+	ratingUC  RatingUsecase
+	gameUC    GameUsecase
+	responder restapi.Responder
+}
+
+func New(log *slog.Logger, ratingUC RatingUsecase, gameUC GameUsecase, responder restapi.Responder) *Handler {
+	log = slogdiscard.LoggerIfNil(log)
+	return &Handler{log: log, ratingUC: ratingUC, gameUC: gameUC, responder: responder}
+}
+
+// parsePlayerID parses the player_id URL param, players are identified by the user id.
+func parsePlayerID(r *http.Request) (domain.PlayerID, error) {
+	userID, err := uuid.Parse(chi.URLParam(r, "player_id"))
+	if err != nil {
+		return domain.PlayerID{}, err
+	}
+	return domain.UserPlayerID(userID), nil
+}
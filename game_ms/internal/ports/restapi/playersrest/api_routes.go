@@ -0,0 +1,10 @@
+package playersrest
+
+import "github.com/go-chi/chi/v5"
+
+func (h *Handler) SetupRoutes(r chi.Router) {
+	r.Get("/api/v1/players/{player_id}/rating", h.GetRating())
+	r.Get("/api/v1/players/{player_id}/games", h.ListGames())
+	r.Get("/api/v1/players/{player_id}/stats", h.GetStats())
+	r.Get("/api/v1/leaderboards/{leaderboard}", h.GetLeaderboard())
+}
@@ -0,0 +1,142 @@
+package playersrest
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultPlayerGamesLimit = 20
+	maxPlayerGamesLimit     = 100
+)
+
+type PlayerGameResp struct {
+	GameID uuid.UUID   `json:"game_id"`
+	Mode   string      `json:"mode"`
+	Side   domain.Side `json:"side"`
+	// empty if the opponent hasn't joined
+	OpponentID string `json:"opponent_id"`
+	Result     string `json:"result"`
+	Reason     string `json:"reason"`
+	// plies of the game
+	Moves     int       `json:"moves"`
+	Rated     bool      `json:"rated"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (r *PlayerGameResp) FromDomain(g *domain.Game, playerID domain.PlayerID) {
+	side := g.PlayerSide(playerID)
+
+	r.GameID = g.ID
+	r.Mode = g.Mode
+	r.Side = side
+	if opponent := g.Opponent(side); opponent != nil {
+		r.OpponentID = opponent.ID.ClientID
+	}
+	r.Result = g.Outcome(side).String()
+	r.Reason = g.FinishReason.String()
+	r.Moves = len(g.Moves)
+	r.Rated = g.Rated
+	r.CreatedAt = g.CreatedAt
+}
+
+type PlayerGamesResp struct {
+	Games  []PlayerGameResp `json:"games"`
+	Total  int              `json:"total"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+}
+
+type PlayerStatsResp struct {
+	ClientID string `json:"client_id"`
+	Games    int    `json:"games"`
+	Wins     int    `json:"wins"`
+	Losses   int    `json:"losses"`
+	Draws    int    `json:"draws"`
+	// plies per game
+	AverageMoves     float64 `json:"average_moves"`
+	FirstMoveGames   int     `json:"first_move_games"`
+	FirstMoveWinRate float64 `json:"first_move_win_rate"`
+	// wins by the win line with a place freed by a disappeared figure
+	DisappearingWins    int     `json:"disappearing_wins"`
+	DisappearingWinRate float64 `json:"disappearing_win_rate"`
+}
+
+func (r *PlayerStatsResp) FromDomain(playerID domain.PlayerID, stats domain.PlayerStats) {
+	r.ClientID = playerID.ClientID
+	r.Games = stats.Games
+	r.Wins = stats.Wins
+	r.Losses = stats.Losses
+	r.Draws = stats.Draws
+	r.AverageMoves = stats.AverageMoves()
+	r.FirstMoveGames = stats.FirstMoveGames
+	r.FirstMoveWinRate = stats.FirstMoveWinRate()
+	r.DisappearingWins = stats.DisappearingWins
+	r.DisappearingWinRate = stats.DisappearingWinRate()
+}
+
+// ListGames returns the finished games of the player from newest to oldest.
+func (h *Handler) ListGames() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		playerID, err := parsePlayerID(r)
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		finished := domain.Finished
+		filter := domain.GameFilter{State: &finished, PlayerID: playerID}
+
+		filter.Limit, filter.Offset, err = restapi.ParsePagination(r, defaultPlayerGamesLimit, maxPlayerGamesLimit)
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		games, total, err := h.gameUC.ListGames(r.Context(), filter)
+		if err != nil {
+			log.Error("uc list player games", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := &PlayerGamesResp{
+			Games:  make([]PlayerGameResp, len(games)),
+			Total:  total,
+			Limit:  filter.Limit,
+			Offset: filter.Offset,
+		}
+		for i := range games {
+			resp.Games[i].FromDomain(games[i], playerID)
+		}
+
+		h.responder.Respond(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) GetStats() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		playerID, err := parsePlayerID(r)
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		stats, err := h.gameUC.GetPlayerStats(r.Context(), playerID)
+		if err != nil {
+			log.Error("uc get player stats", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := &PlayerStatsResp{}
+		resp.FromDomain(playerID, stats)
+		h.responder.Respond(w, http.StatusOK, resp)
+	}
+}
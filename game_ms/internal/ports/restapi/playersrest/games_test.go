@@ -0,0 +1,120 @@
+package playersrest
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"encoding/json"
+	"errors"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"testing"
+)
+
+type playerGamesUC struct {
+	games []*domain.Game
+	stats domain.PlayerStats
+	err   error
+	// the filter of the last call
+	filter domain.GameFilter
+}
+
+func (uc *playerGamesUC) ListGames(ctx context.Context, filter domain.GameFilter) ([]*domain.Game, int, error) {
+	uc.filter = filter
+	return uc.games, 7, uc.err
+}
+
+func (uc *playerGamesUC) GetPlayerStats(ctx context.Context, playerID domain.PlayerID) (domain.PlayerStats, error) {
+	return uc.stats, uc.err
+}
+
+func TestHandler_ListGames(t *testing.T) {
+	player := domain.UserPlayerID(uuid.New())
+	opponent := domain.UserPlayerID(uuid.New())
+
+	g := &domain.Game{ID: uuid.New(), Mode: domain.ModeWithFriend, State: domain.Finished,
+		XPlayer: &domain.Player{ID: opponent}, OPlayer: &domain.Player{ID: player},
+		Moves: make([]domain.Move, 5), Winner: domain.OWin, FinishReason: domain.WinLineFinish, Rated: true}
+	uc := &playerGamesUC{games: []*domain.Game{g}}
+
+	w := servePlayers(nil, uc, "/api/v1/players/"+player.UserID.String()+"/games?limit=5&offset=10")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	finished := domain.Finished
+	assert.Equal(t, domain.GameFilter{State: &finished, PlayerID: player, Limit: 5, Offset: 10}, uc.filter,
+		"the finished games of the player")
+
+	resp := &PlayerGamesResp{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+	assert.Equal(t, 7, resp.Total)
+	assert.Equal(t, 5, resp.Limit)
+	assert.Equal(t, 10, resp.Offset)
+	require.Len(t, resp.Games, 1)
+
+	game := resp.Games[0]
+	assert.Equal(t, g.ID, game.GameID)
+	assert.Equal(t, domain.OSide, game.Side)
+	assert.Equal(t, opponent.ClientID, game.OpponentID)
+	assert.Equal(t, "win", game.Result)
+	assert.Equal(t, "win_line", game.Reason)
+	assert.Equal(t, 5, game.Moves)
+	assert.True(t, game.Rated)
+
+	w = servePlayers(nil, uc, "/api/v1/players/"+player.UserID.String()+"/games")
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, defaultPlayerGamesLimit, uc.filter.Limit)
+	assert.Zero(t, uc.filter.Offset)
+}
+
+func TestHandler_GetStats(t *testing.T) {
+	player := domain.UserPlayerID(uuid.New())
+	uc := &playerGamesUC{stats: domain.PlayerStats{Games: 4, Wins: 2, Losses: 1, Draws: 1, Moves: 30,
+		FirstMoveGames: 2, FirstMoveWins: 1, DisappearingWins: 1}}
+
+	w := servePlayers(nil, uc, "/api/v1/players/"+player.UserID.String()+"/stats")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	resp := &PlayerStatsResp{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+	assert.Equal(t, PlayerStatsResp{ClientID: player.ClientID, Games: 4, Wins: 2, Losses: 1, Draws: 1,
+		AverageMoves: 7.5, FirstMoveGames: 2, FirstMoveWinRate: 0.5,
+		DisappearingWins: 1, DisappearingWinRate: 0.5}, *resp)
+}
+
+func TestHandler_PlayerErrors(t *testing.T) {
+	playerID := uuid.NewString()
+	storeErr := errors.New("store is down")
+
+	tcases := []struct {
+		Name   string
+		Target string
+		Err    error
+		Status int
+		Code   restapi.ErrorCode
+	}{
+		{Name: "games of invalid player", Target: "/api/v1/players/alice/games",
+			Status: http.StatusBadRequest, Code: restapi.CodeBadRequest},
+		{Name: "stats of invalid player", Target: "/api/v1/players/42/stats",
+			Status: http.StatusBadRequest, Code: restapi.CodeBadRequest},
+		{Name: "rating of invalid player", Target: "/api/v1/players/alice/rating",
+			Status: http.StatusBadRequest, Code: restapi.CodeBadRequest},
+		{Name: "zero limit", Target: "/api/v1/players/" + playerID + "/games?limit=0",
+			Status: http.StatusBadRequest, Code: restapi.CodeInvalidPagination},
+		{Name: "limit over max", Target: "/api/v1/players/" + playerID + "/games?limit=101",
+			Status: http.StatusBadRequest, Code: restapi.CodeInvalidPagination},
+		{Name: "non-numeric offset", Target: "/api/v1/players/" + playerID + "/games?offset=x",
+			Status: http.StatusBadRequest, Code: restapi.CodeInvalidPagination},
+		{Name: "games store error", Target: "/api/v1/players/" + playerID + "/games", Err: storeErr,
+			Status: http.StatusInternalServerError, Code: restapi.CodeInternal},
+		{Name: "stats store error", Target: "/api/v1/players/" + playerID + "/stats", Err: storeErr,
+			Status: http.StatusInternalServerError, Code: restapi.CodeInternal},
+	}
+
+	for _, tc := range tcases {
+		w := servePlayers(nil, &playerGamesUC{err: tc.Err}, tc.Target)
+		require.Equal(t, tc.Status, w.Code, tc.Name)
+		assert.Equal(t, tc.Code, errorCode(t, w), tc.Name)
+	}
+}
@@ -0,0 +1,78 @@
+package playersrest
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"github.com/go-chi/chi/v5"
+	"log/slog"
+	"net/http"
+)
+
+const (
+	defaultLeaderboardLimit = 10
+	maxLeaderboardLimit     = 100
+)
+
+type LeaderboardEntryResp struct {
+	Rank     int    `json:"rank"`
+	ClientID string `json:"client_id"`
+	// value the leaderboard is ordered by
+	Score int `json:"score"`
+	RatingResp
+}
+
+func (r *LeaderboardEntryResp) FromDomain(leaderboard domain.Leaderboard, entry domain.LeaderboardEntry) {
+	r.Rank = entry.Rank
+	r.ClientID = entry.Rating.PlayerID.ClientID
+	r.Score = leaderboard.Score(entry.Rating)
+	r.RatingResp.FromDomain(entry.Rating)
+}
+
+type LeaderboardResp struct {
+	Leaderboard string                 `json:"leaderboard"`
+	Pool        string                 `json:"pool"`
+	Entries     []LeaderboardEntryResp `json:"entries"`
+	Limit       int                    `json:"limit"`
+	Offset      int                    `json:"offset"`
+}
+
+// GetLeaderboard returns the top of the leaderboard of the rating pool,
+// the pool is the mode and board preset as in the rating responses.
+func (h *Handler) GetLeaderboard() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		leaderboard, err := domain.ParseLeaderboard(chi.URLParam(r, "leaderboard"))
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		limit, offset, err := restapi.ParsePagination(r, defaultLeaderboardLimit, maxLeaderboardLimit)
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		pool := r.URL.Query().Get("pool")
+
+		entries, err := h.ratingUC.GetLeaderboard(r.Context(), pool, leaderboard, limit, offset)
+		if err != nil {
+			log.Error("get leaderboard", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := &LeaderboardResp{
+			Leaderboard: leaderboard.String(),
+			Pool:        pool,
+			Entries:     make([]LeaderboardEntryResp, len(entries)),
+			Limit:       limit,
+			Offset:      offset,
+		}
+		for i := range entries {
+			resp.Entries[i].FromDomain(leaderboard, entries[i])
+		}
+
+		h.responder.Respond(w, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,102 @@
+package playersrest
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/adapters/mapstore"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"dataxo-backend-game-ms/internal/usecases/ratinguc"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func servePlayers(ratingUC RatingUsecase, gameUC GameUsecase, target string) *httptest.ResponseRecorder {
+	responder := restapi.NewJsonResponder(nil, nil)
+	router := chi.NewRouter()
+	New(nil, ratingUC, gameUC, responder).SetupRoutes(router)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) restapi.ErrorCode {
+	httpErr := &restapi.HTTPError{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), httpErr))
+	return httpErr.Code
+}
+
+func TestHandler_GetLeaderboard(t *testing.T) {
+	ctx := context.Background()
+	pool := domain.RatingPool(domain.ModeWithFriend, domain.DisappearingModeConfig{
+		PlayerFiguresLimit: 3, WinLineLength: 3, BoardWidth: 3, BoardHeight: 3})
+
+	ratingRepo := mapstore.NewRatingRepo()
+	ratingUC := ratinguc.New(ratingRepo, mapstore.NewUserRepo(), nil)
+
+	ratings := make([]domain.Rating, 3)
+	for i, v := range []struct{ value, wins int }{{1510, 4}, {1550, 1}, {1490, 2}} {
+		ratings[i] = domain.NewRating(domain.UserPlayerID(uuid.New()), pool)
+		ratings[i].Value, ratings[i].Wins = v.value, v.wins
+		ratings[i].UpdatedAt = time.Unix(1000, 0).UTC()
+	}
+	require.NoError(t, ratingRepo.SaveRatings(ctx, ratings...))
+
+	leaderboard := func(name, query string) *LeaderboardResp {
+		w := servePlayers(ratingUC, nil, "/api/v1/leaderboards/"+name+"?pool="+url.QueryEscape(pool)+query)
+		require.Equal(t, http.StatusOK, w.Code, name)
+
+		resp := &LeaderboardResp{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), resp))
+		return resp
+	}
+
+	resp := leaderboard("rating", "")
+	assert.Equal(t, "rating", resp.Leaderboard)
+	assert.Equal(t, pool, resp.Pool)
+	assert.Equal(t, defaultLeaderboardLimit, resp.Limit)
+	require.Len(t, resp.Entries, 3)
+	assert.Equal(t, LeaderboardEntryResp{Rank: 1, ClientID: ratings[1].PlayerID.ClientID, Score: 1550,
+		RatingResp: RatingResp{Pool: pool, Rating: 1550, Wins: 1, Provisional: true, UpdatedAt: ratings[1].UpdatedAt}},
+		resp.Entries[0])
+
+	resp = leaderboard("wins", "&limit=1&offset=1")
+	assert.Equal(t, 1, resp.Limit)
+	assert.Equal(t, 1, resp.Offset)
+	require.Len(t, resp.Entries, 1)
+	assert.Equal(t, 2, resp.Entries[0].Rank, "the rank counts the offset")
+	assert.Equal(t, ratings[2].PlayerID.ClientID, resp.Entries[0].ClientID)
+	assert.Equal(t, 2, resp.Entries[0].Score)
+
+	resp = leaderboard("win_streak", "&offset=5")
+	assert.Empty(t, resp.Entries, "offset after the last rating")
+}
+
+func TestHandler_GetLeaderboardErrors(t *testing.T) {
+	ratingUC := ratinguc.New(mapstore.NewRatingRepo(), mapstore.NewUserRepo(), nil)
+
+	tcases := []struct {
+		Name   string
+		Target string
+		Code   restapi.ErrorCode
+	}{
+		{Name: "unknown leaderboard", Target: "/api/v1/leaderboards/losses?pool=a", Code: restapi.CodeInvalidLeaderboard},
+		{Name: "missing pool", Target: "/api/v1/leaderboards/rating", Code: restapi.CodeMissingRatingPool},
+		{Name: "negative limit", Target: "/api/v1/leaderboards/rating?pool=a&limit=-1", Code: restapi.CodeInvalidPagination},
+		{Name: "negative offset", Target: "/api/v1/leaderboards/rating?pool=a&offset=-1", Code: restapi.CodeInvalidPagination},
+	}
+
+	for _, tc := range tcases {
+		w := servePlayers(ratingUC, nil, tc.Target)
+		require.Equal(t, http.StatusBadRequest, w.Code, tc.Name)
+		assert.Equal(t, tc.Code, errorCode(t, w), tc.Name)
+	}
+}
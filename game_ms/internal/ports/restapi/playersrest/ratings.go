@@ -0,0 +1,72 @@
+package playersrest
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type RatingResp struct {
+	Pool   string `json:"pool"`
+	Rating int    `json:"rating"`
+	Games  int    `json:"games"`
+	Wins   int    `json:"wins"`
+	Losses int    `json:"losses"`
+	Draws  int    `json:"draws"`
+	// current series of wins in a row
+	WinStreak     int       `json:"win_streak"`
+	BestWinStreak int       `json:"best_win_streak"`
+	Provisional   bool      `json:"provisional"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+func (r *RatingResp) FromDomain(rating domain.Rating) {
+	r.Pool = rating.Pool
+	r.Rating = rating.Value
+	r.Games = rating.Games
+	r.Wins = rating.Wins
+	r.Losses = rating.Losses
+	r.Draws = rating.Draws
+	r.WinStreak = rating.WinStreak
+	r.BestWinStreak = rating.BestWinStreak
+	r.Provisional = rating.IsProvisional()
+	r.UpdatedAt = rating.UpdatedAt
+}
+
+type PlayerRatingResp struct {
+	ClientID string `json:"client_id"`
+	// the player has no ratings until the first rated game
+	Ratings []RatingResp `json:"ratings"`
+}
+
+func (r *PlayerRatingResp) FromDomain(playerID domain.PlayerID, ratings []domain.Rating) {
+	r.ClientID = playerID.ClientID
+	r.Ratings = make([]RatingResp, len(ratings))
+	for i := range ratings {
+		r.Ratings[i].FromDomain(ratings[i])
+	}
+}
+
+// GetRating returns the ratings of the player in every pool the player has played.
+func (h *Handler) GetRating() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		playerID, err := parsePlayerID(r)
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		ratings, err := h.ratingUC.GetRatings(r.Context(), playerID)
+		if err != nil {
+			log.Error("get ratings", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := &PlayerRatingResp{}
+		resp.FromDomain(playerID, ratings)
+		h.responder.Respond(w, http.StatusOK, resp)
+	}
+}
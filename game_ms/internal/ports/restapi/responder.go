@@ -7,11 +7,18 @@ import (
 
 type Responder interface {
 	Respond(w http.ResponseWriter, code int, data interface{})
-	RespondError(w http.ResponseWriter, code int, err error)
+	RespondError(w http.ResponseWriter, r *http.Request, code int, err error)
 }
 
 type WsResponder interface {
 	Marshal(data interface{}) ([]byte, error)
 	RespondWs(session *melody.Session, data interface{})
+	RespondWsBytes(session *melody.Session, b []byte)
 	RespondErrorWs(session *melody.Session, err error)
 }
+
+// WsCodec encodes and decodes WebSocket messages of the same format.
+type WsCodec interface {
+	WsResponder
+	Unmarshal(data []byte, v interface{}) error
+}
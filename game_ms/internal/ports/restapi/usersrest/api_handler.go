@@ -0,0 +1,27 @@
+package usersrest
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"dataxo-backend-game-ms/pkg/slogdiscard"
+	"github.com/google/uuid"
+	"log/slog"
+)
+
+type UserUsecase interface {
+	GetUser(ctx context.Context, userID uuid.UUID) (*domain.User, error)
+	Register(ctx context.Context, userID uuid.UUID, username, password string) (*domain.User, error)
+	UpdateProfile(ctx context.Context, userID uuid.UUID, displayName string) (*domain.User, error)
+}
+
+type Handler struct {
+	log       *slog.Logger
+	userUC    UserUsecase
+	responder restapi.Responder
+}
+
+func New(log *slog.Logger, userUC UserUsecase, responder restapi.Responder) *Handler {
+	log = slogdiscard.LoggerIfNil(log)
+	return &Handler{log: log, userUC: userUC, responder: responder}
+}
@@ -0,0 +1,10 @@
+package usersrest
+
+import "github.com/go-chi/chi/v5"
+
+func (h *Handler) SetupRoutes(r chi.Router) {
+	r.Get("/api/v1/users/me", h.GetMe())
+	r.Patch("/api/v1/users/me", h.UpdateMe())
+	r.Post("/api/v1/users/me/register", h.Register())
+	r.Get("/api/v1/users/{user_id}", h.GetUser())
+}
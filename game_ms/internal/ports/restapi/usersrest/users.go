@@ -0,0 +1,137 @@
+package usersrest
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/ports/restapi"
+	"encoding/json"
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type UserResp struct {
+	UserID       uuid.UUID  `json:"user_id"`
+	Username     string     `json:"username,omitempty"`
+	DisplayName  string     `json:"display_name"`
+	Guest        bool       `json:"guest"`
+	CreatedAt    time.Time  `json:"created_at"`
+	RegisteredAt *time.Time `json:"registered_at,omitempty"`
+}
+
+func (r *UserResp) FromDomain(u *domain.User) {
+	r.UserID = u.ID
+	r.Username = u.Username
+	r.DisplayName = u.DisplayName
+	r.Guest = u.IsGuest()
+	r.CreatedAt = u.CreatedAt
+
+	if !u.IsGuest() {
+		registeredAt := u.RegisteredAt
+		r.RegisteredAt = &registeredAt
+	}
+}
+
+type RegisterReq struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type UpdateProfileReq struct {
+	DisplayName string `json:"display_name"`
+}
+
+func (h *Handler) GetMe() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		playerID, err := restapi.PlayerIDFromContext(r.Context())
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusUnauthorized, err)
+			return
+		}
+
+		h.respondUser(w, r, playerID.UserID)
+	}
+}
+
+func (h *Handler) GetUser() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		userID, err := uuid.Parse(chi.URLParam(r, "user_id"))
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		h.respondUser(w, r, userID)
+	}
+}
+
+// Register upgrades the guest of the request token to the registered account.
+func (h *Handler) Register() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		playerID, err := restapi.PlayerIDFromContext(r.Context())
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusUnauthorized, err)
+			return
+		}
+
+		req := &RegisterReq{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u, err := h.userUC.Register(r.Context(), playerID.UserID, req.Username, req.Password)
+		if err != nil {
+			log.Info("uc register", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := &UserResp{}
+		resp.FromDomain(u)
+		h.responder.Respond(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) UpdateMe() http.HandlerFunc {
+	log := h.log
+	return func(w http.ResponseWriter, r *http.Request) {
+		playerID, err := restapi.PlayerIDFromContext(r.Context())
+		if err != nil {
+			h.responder.RespondError(w, r, http.StatusUnauthorized, err)
+			return
+		}
+
+		req := &UpdateProfileReq{}
+		if err := json.NewDecoder(r.Body).Decode(req); err != nil {
+			h.responder.RespondError(w, r, http.StatusBadRequest, err)
+			return
+		}
+
+		u, err := h.userUC.UpdateProfile(r.Context(), playerID.UserID, req.DisplayName)
+		if err != nil {
+			log.Info("uc update profile", slog.Any("error", err))
+			h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+			return
+		}
+
+		resp := &UserResp{}
+		resp.FromDomain(u)
+		h.responder.Respond(w, http.StatusOK, resp)
+	}
+}
+
+func (h *Handler) respondUser(w http.ResponseWriter, r *http.Request, userID uuid.UUID) {
+	u, err := h.userUC.GetUser(r.Context(), userID)
+	if err != nil {
+		h.log.Info("uc get user", slog.Any("error", err))
+		h.responder.RespondError(w, r, http.StatusInternalServerError, err)
+		return
+	}
+
+	resp := &UserResp{}
+	resp.FromDomain(u)
+	h.responder.Respond(w, http.StatusOK, resp)
+}
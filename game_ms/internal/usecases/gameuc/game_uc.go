@@ -3,10 +3,12 @@ package gameuc
 import (
 	"context"
 	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/usecases/gameuc/notation"
 	"dataxo-backend-game-ms/pkg/slogdiscard"
 	"github.com/google/uuid"
 	"log/slog"
 	"math/rand"
+	"time"
 )
 
 type GameRepository interface {
@@ -14,23 +16,70 @@ type GameRepository interface {
 	UpdateGameState(ctx context.Context, gameID uuid.UUID, state domain.State) error
 	UpdateGame(ctx context.Context, g *domain.Game) error
 	GetGame(ctx context.Context, gameID uuid.UUID) (*domain.Game, error)
+	ListGames(ctx context.Context, filter domain.GameFilter) ([]*domain.Game, int, error)
+	CountGamesByState(ctx context.Context) (map[domain.State]int, error)
 	GetPlayers(ctx context.Context, gameID uuid.UUID) (x *domain.Player, o *domain.Player, err error)
 	AddGamePlayer(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID, side domain.Side) error
+	AppendHistory(ctx context.Context, gameID uuid.UUID, events ...domain.HistoryEvent) error
+	GetHistory(ctx context.Context, gameID uuid.UUID) ([]domain.HistoryEvent, error)
+}
+
+type SeriesRepository interface {
+	CreateSeries(ctx context.Context, bestOf int, firstGameID uuid.UUID) (*domain.Series, error)
+	GetSeries(ctx context.Context, seriesID uuid.UUID) (*domain.Series, error)
+	UpdateSeries(ctx context.Context, s *domain.Series) error
 }
 
 type GameMode interface {
 	IterateGame(ctx context.Context, g *domain.Game, move domain.Move) (domain.MakeMoveResult, error)
+	UndoLastMove(ctx context.Context, g *domain.Game) (domain.MakeMoveResult, error)
+	ReplayBoard(ctx context.Context, g *domain.Game, ply int) (domain.BoardSnapshot, error)
 	GetConfig() domain.DisappearingModeConfig
 }
 
+// Rater updates the ratings of the players by the finished game.
+type Rater interface {
+	RateGame(ctx context.Context, g *domain.Game) ([]domain.RatingChange, error)
+}
+
+// Metrics records the outcomes of the moves.
+type Metrics interface {
+	ObserveMove(elapsed time.Duration, err error)
+}
+
 type GameUC struct {
-	gameRepo GameRepository
-	gameMode GameMode
-	log      *slog.Logger
+	gameRepo   GameRepository
+	seriesRepo SeriesRepository
+	gameMode   GameMode
+	// nil if the games aren't rated
+	rater Rater
+	// nil if the metrics aren't collected
+	metrics Metrics
+	log     *slog.Logger
+}
+
+type Opt func(uc *GameUC)
+
+func WithRater(rater Rater) Opt {
+	return func(uc *GameUC) {
+		uc.rater = rater
+	}
+}
+
+func WithMetrics(metrics Metrics) Opt {
+	return func(uc *GameUC) {
+		uc.metrics = metrics
+	}
 }
 
-func New(gameRepo GameRepository, gameMode GameMode, log *slog.Logger) *GameUC {
-	return &GameUC{gameRepo: gameRepo, gameMode: gameMode, log: slogdiscard.LoggerIfNil(log)}
+func New(gameRepo GameRepository, seriesRepo SeriesRepository, gameMode GameMode, log *slog.Logger, opts ...Opt) *GameUC {
+	uc := &GameUC{gameRepo: gameRepo, seriesRepo: seriesRepo, gameMode: gameMode, log: slogdiscard.LoggerIfNil(log)}
+
+	for _, opt := range opts {
+		opt(uc)
+	}
+
+	return uc
 }
 
 func (uc *GameUC) CreateGame(ctx context.Context, plID domain.PlayerID, mode string, params domain.ModeParams) (*domain.Game, error) {
@@ -46,13 +95,68 @@ func (uc *GameUC) CreateGame(ctx context.Context, plID domain.PlayerID, mode str
 		return nil, domain.ErrInvalidSide
 	}
 
-	return uc.gameRepo.CreateGame(ctx, plID, side, mode, uc.gameMode.GetConfig())
+	if params.BestOf > 1 {
+		err := domain.ValidateSeriesBestOf(params.BestOf)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	g, err := uc.gameRepo.CreateGame(ctx, plID, side, mode, uc.gameMode.GetConfig())
+	if err != nil {
+		return nil, err
+	}
+
+	if params.BestOf <= 1 && !params.Rated {
+		return g, nil
+	}
+
+	g.Rated = params.Rated
+
+	if params.BestOf > 1 {
+		series, err := uc.seriesRepo.CreateSeries(ctx, params.BestOf, g.ID)
+		if err != nil {
+			return nil, err
+		}
+		g.SeriesID = series.ID
+	}
+
+	err = uc.gameRepo.UpdateGame(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
 }
 
 func (uc *GameUC) GetGame(ctx context.Context, gameID uuid.UUID) (*domain.Game, error) {
 	return uc.gameRepo.GetGame(ctx, gameID)
 }
 
+func (uc *GameUC) ListGames(ctx context.Context, filter domain.GameFilter) ([]*domain.Game, int, error) {
+	return uc.gameRepo.ListGames(ctx, filter)
+}
+
+func (uc *GameUC) CountGamesByState(ctx context.Context) (map[domain.State]int, error) {
+	return uc.gameRepo.CountGamesByState(ctx)
+}
+
+// GetPlayerStats summarizes all the finished games of the player.
+func (uc *GameUC) GetPlayerStats(ctx context.Context, playerID domain.PlayerID) (domain.PlayerStats, error) {
+	finished := domain.Finished
+	games, _, err := uc.gameRepo.ListGames(ctx, domain.GameFilter{State: &finished, PlayerID: playerID})
+	if err != nil {
+		return domain.PlayerStats{}, err
+	}
+
+	var stats domain.PlayerStats
+	for _, g := range games {
+		stats.Add(g, playerID)
+	}
+
+	return stats, nil
+}
+
 func (uc *GameUC) JoinGame(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID) (domain.JoinGameResult, error) {
 	xPlayer, oPlayer, err := uc.gameRepo.GetPlayers(ctx, gameID)
 	if err != nil {
@@ -164,6 +268,15 @@ func (uc *GameUC) GetSide(ctx context.Context, gameID uuid.UUID, playerID domain
 }*/
 
 func (uc *GameUC) MakeMove(ctx context.Context, gameID uuid.UUID, move domain.Move) (domain.MakeMoveResult, error) {
+	start := time.Now()
+	res, err := uc.makeMove(ctx, gameID, move)
+	if uc.metrics != nil {
+		uc.metrics.ObserveMove(time.Since(start), err)
+	}
+	return res, err
+}
+
+func (uc *GameUC) makeMove(ctx context.Context, gameID uuid.UUID, move domain.Move) (domain.MakeMoveResult, error) {
 	g, err := uc.gameRepo.GetGame(ctx, gameID)
 	if err != nil {
 		return domain.MakeMoveResult{}, err
@@ -181,10 +294,429 @@ func (uc *GameUC) MakeMove(ctx context.Context, gameID uuid.UUID, move domain.Mo
 		return res, err
 	}
 
+	g.TakebackProposedBy = domain.NoneSide
+	g.DrawOfferedBy = domain.NoneSide
+
 	err = uc.gameRepo.UpdateGame(ctx, g)
 	if err != nil {
 		return res, err
 	}
 
+	err = uc.appendHistory(ctx, g, move.Side, res.Events, len(g.Moves), false)
+	if err != nil {
+		return res, err
+	}
+
+	if !res.GameFinished {
+		return res, nil
+	}
+
+	uc.onGameFinished(ctx, g, &res)
+	return res, nil
+}
+
+func (uc *GameUC) OfferDraw(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID) (domain.Side, error) {
+	g, side, err := uc.getStartedGameWithSide(ctx, gameID, playerID)
+	if err != nil {
+		return domain.NoneSide, err
+	}
+
+	if g.DrawOfferedBy != domain.NoneSide {
+		return domain.NoneSide, &domain.GameErrorWithID{Err: domain.ErrDrawAlreadyOffered, ID: g.ID}
+	}
+
+	g.DrawOfferedBy = side
+	err = uc.gameRepo.UpdateGame(ctx, g)
+	if err != nil {
+		return domain.NoneSide, err
+	}
+
+	return side, nil
+}
+
+// AnswerDraw accepts or declines the opponent's draw offer.
+// On acceptance the game is finished with domain.Draw.
+func (uc *GameUC) AnswerDraw(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID, accept bool) (domain.MakeMoveResult, error) {
+	g, side, err := uc.getStartedGameWithSide(ctx, gameID, playerID)
+	if err != nil {
+		return domain.MakeMoveResult{}, err
+	}
+
+	if g.DrawOfferedBy == domain.NoneSide {
+		return domain.MakeMoveResult{}, &domain.GameErrorWithID{Err: domain.ErrDrawNotOffered, ID: g.ID}
+	}
+	if g.DrawOfferedBy == side {
+		return domain.MakeMoveResult{}, &domain.PlayerError{Err: domain.ErrCantAnswerOwnDrawOffer, PlayerID: playerID}
+	}
+
+	g.DrawOfferedBy = domain.NoneSide
+
+	if !accept {
+		return domain.MakeMoveResult{}, uc.gameRepo.UpdateGame(ctx, g)
+	}
+
+	return uc.finishGame(ctx, g, side, domain.Draw, domain.DrawAgreementFinish, domain.DrawAgreementHistoryEvent)
+}
+
+// Resign finishes the game with the opponent of the player as the winner.
+func (uc *GameUC) Resign(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID) (domain.MakeMoveResult, error) {
+	g, side, err := uc.getStartedGameWithSide(ctx, gameID, playerID)
+	if err != nil {
+		return domain.MakeMoveResult{}, err
+	}
+
+	return uc.finishGame(ctx, g, side, side.Opponent().ToWinSide(), domain.ResignationFinish, domain.ResignationHistoryEvent)
+}
+
+// finishGame finishes the game by the action of the player of actorSide,
+// the action is logged as the history event of the kind.
+func (uc *GameUC) finishGame(ctx context.Context, g *domain.Game, actorSide domain.Side,
+	winner domain.WinSide, reason domain.FinishReason, kind domain.HistoryEventKind) (domain.MakeMoveResult, error) {
+	g.State = domain.Finished
+	g.Winner = winner
+	g.FinishReason = reason
+	g.WinSequence = make([]domain.Move, 0)
+	g.TakebackProposedBy = domain.NoneSide
+	g.DrawOfferedBy = domain.NoneSide
+
+	err := uc.gameRepo.UpdateGame(ctx, g)
+	if err != nil {
+		return domain.MakeMoveResult{}, err
+	}
+
+	err = uc.gameRepo.AppendHistory(ctx, g.ID, domain.HistoryEvent{
+		Kind:       kind,
+		MoveNumber: len(g.Moves),
+		Actor:      playerOfSide(g, actorSide),
+		Time:       time.Now(),
+	})
+	if err != nil {
+		return domain.MakeMoveResult{}, err
+	}
+
+	res := domain.MakeMoveResult{GameFinished: true}
+	uc.onGameFinished(ctx, g, &res)
 	return res, nil
 }
+
+// onGameFinished fills the finish details of the result and
+// processes everything that depends on the game result.
+// The game result is already saved, so the failures are logged only.
+func (uc *GameUC) onGameFinished(ctx context.Context, g *domain.Game, res *domain.MakeMoveResult) {
+	res.Winner = g.Winner
+	res.FinishReason = g.FinishReason
+
+	if uc.rater != nil {
+		changes, err := uc.rater.RateGame(ctx, g)
+		if err != nil {
+			uc.log.Error("rate game",
+				slog.String("game_id", g.ID.String()),
+				slog.Any("error", err),
+			)
+		}
+		res.RatingChanges = changes
+	}
+
+	nextGameID, err := uc.recordSeriesGame(ctx, g)
+	if err != nil {
+		// the clients get no next game, the series can be continued with a new game
+		uc.log.Error("record series game",
+			slog.String("game_id", g.ID.String()),
+			slog.String("series_id", g.SeriesID.String()),
+			slog.Any("error", err),
+		)
+		return
+	}
+	res.NextGameID = nextGameID
+}
+
+func (uc *GameUC) ProposeTakeback(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID) (domain.Side, error) {
+	g, side, err := uc.getStartedGameWithSide(ctx, gameID, playerID)
+	if err != nil {
+		return domain.NoneSide, err
+	}
+
+	if len(g.Moves) == 0 {
+		return domain.NoneSide, &domain.GameErrorWithID{Err: domain.ErrNoMoveToTakeback, ID: g.ID}
+	}
+	if g.Moves[len(g.Moves)-1].Side != side {
+		return domain.NoneSide, &domain.PlayerError{Err: domain.ErrLastMoveIsNotYours, PlayerID: playerID}
+	}
+	if g.TakebackProposedBy != domain.NoneSide {
+		return domain.NoneSide, &domain.GameErrorWithID{Err: domain.ErrTakebackAlreadyProposed, ID: g.ID}
+	}
+
+	g.TakebackProposedBy = side
+	err = uc.gameRepo.UpdateGame(ctx, g)
+	if err != nil {
+		return domain.NoneSide, err
+	}
+
+	return side, nil
+}
+
+// AnswerTakeback accepts or declines the opponent's takeback proposal.
+// On acceptance the last move is rolled back and the compensating events are returned.
+func (uc *GameUC) AnswerTakeback(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID, accept bool) (domain.MakeMoveResult, error) {
+	g, side, err := uc.getStartedGameWithSide(ctx, gameID, playerID)
+	if err != nil {
+		return domain.MakeMoveResult{}, err
+	}
+
+	if g.TakebackProposedBy == domain.NoneSide {
+		return domain.MakeMoveResult{}, &domain.GameErrorWithID{Err: domain.ErrTakebackNotProposed, ID: g.ID}
+	}
+	if g.TakebackProposedBy == side {
+		return domain.MakeMoveResult{}, &domain.PlayerError{Err: domain.ErrCantAnswerOwnTakeback, PlayerID: playerID}
+	}
+
+	var res domain.MakeMoveResult
+	if accept {
+		res, err = uc.gameMode.UndoLastMove(ctx, g)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	// the taken back move is of the proposer
+	proposer := g.TakebackProposedBy
+	g.TakebackProposedBy = domain.NoneSide
+	err = uc.gameRepo.UpdateGame(ctx, g)
+	if err != nil {
+		return domain.MakeMoveResult{}, err
+	}
+
+	if !accept {
+		return res, nil
+	}
+
+	err = uc.appendHistory(ctx, g, proposer, res.Events, len(g.Moves)+1, true)
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+func (uc *GameUC) GetHistory(ctx context.Context, gameID uuid.UUID) ([]domain.HistoryEvent, error) {
+	return uc.gameRepo.GetHistory(ctx, gameID)
+}
+
+// GetBoard returns the board after the first ply moves of the game.
+// Negative ply means the current board.
+func (uc *GameUC) GetBoard(ctx context.Context, gameID uuid.UUID, ply int) (domain.BoardSnapshot, error) {
+	g, err := uc.gameRepo.GetGame(ctx, gameID)
+	if err != nil {
+		return domain.BoardSnapshot{}, err
+	}
+
+	if ply < 0 {
+		ply = len(g.Moves)
+	}
+
+	return uc.gameMode.ReplayBoard(ctx, g, ply)
+}
+
+func (uc *GameUC) ExportGame(ctx context.Context, gameID uuid.UUID) (string, error) {
+	g, err := uc.gameRepo.GetGame(ctx, gameID)
+	if err != nil {
+		return "", err
+	}
+
+	return notation.Export(g)
+}
+
+// ImportGame parses the game notation, validates it by replaying
+// all the moves through the game mode and stores the game.
+// ImportGame replays the game notation, the importer must be one of its players.
+// The game is marked as imported, so it isn't rated and isn't listed by default.
+func (uc *GameUC) ImportGame(ctx context.Context, importer domain.PlayerID, text string) (*domain.Game, error) {
+	rec, err := notation.Parse(text)
+	if err != nil {
+		return nil, err
+	}
+
+	header := rec.Header
+	err = domain.ValidateMode(header.Mode)
+	if err != nil {
+		return nil, err
+	}
+	if header.Config != uc.gameMode.GetConfig() {
+		return nil, domain.ErrUnsupportedConfig
+	}
+	if header.XPlayer.ClientID == "" || header.OPlayer.ClientID == "" {
+		return nil, domain.ErrMissingPlayers
+	}
+	// the tags have only client ids, so the importer is matched by it
+	switch importer.ClientID {
+	case header.XPlayer.ClientID:
+		header.XPlayer = importer
+	case header.OPlayer.ClientID:
+		header.OPlayer = importer
+	default:
+		return nil, &domain.PlayerError{Err: domain.ErrPlayerIsNotInGame, PlayerID: importer}
+	}
+
+	replayed := &domain.Game{
+		Mode:        header.Mode,
+		Config:      header.Config,
+		State:       domain.Started,
+		Moves:       make([]domain.Move, 0, len(rec.Moves)),
+		WinSequence: make([]domain.Move, 0),
+	}
+
+	for _, move := range rec.Moves {
+		if replayed.State == domain.Finished {
+			return nil, &domain.MoveError{Err: domain.ErrMovesAfterFinish, Move: move}
+		}
+
+		_, err = uc.gameMode.IterateGame(ctx, replayed, move)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	switch header.Reason {
+	case domain.WinLineFinish, domain.NoPlaceFinish:
+		if replayed.State != domain.Finished || replayed.Winner != header.Result ||
+			replayed.FinishReason != header.Reason {
+			return nil, domain.ErrGameResultMismatch
+		}
+	case domain.DrawAgreementFinish, domain.ResignationFinish:
+		if replayed.State == domain.Finished || header.Result == domain.NoneWin ||
+			(header.Reason == domain.DrawAgreementFinish) != (header.Result == domain.Draw) {
+			return nil, domain.ErrGameResultMismatch
+		}
+
+		replayed.State = domain.Finished
+		replayed.Winner = header.Result
+		replayed.FinishReason = header.Reason
+	default:
+		if replayed.State == domain.Finished || header.Result != domain.NoneWin {
+			return nil, domain.ErrGameResultMismatch
+		}
+	}
+
+	g, err := uc.gameRepo.CreateGame(ctx, header.XPlayer, domain.XSide, header.Mode, header.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	err = uc.gameRepo.AddGamePlayer(ctx, g.ID, header.OPlayer, domain.OSide)
+	if err != nil {
+		return nil, err
+	}
+
+	g.State = replayed.State
+	g.Moves = replayed.Moves
+	g.WinSequence = replayed.WinSequence
+	g.Winner = replayed.Winner
+	g.FinishReason = replayed.FinishReason
+	g.Imported = true
+
+	err = uc.gameRepo.UpdateGame(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (uc *GameUC) appendHistory(ctx context.Context, g *domain.Game, actorSide domain.Side,
+	events []domain.MoveEvent, moveNumber int, takeback bool) error {
+
+	history := domain.NewHistoryEvents(events, moveNumber, playerOfSide(g, actorSide), takeback, time.Now())
+	return uc.gameRepo.AppendHistory(ctx, g.ID, history...)
+}
+
+// playerOfSide returns the zero PlayerID if the side has no player.
+func playerOfSide(g *domain.Game, side domain.Side) domain.PlayerID {
+	switch {
+	case side == domain.XSide && g.XPlayer != nil:
+		return g.XPlayer.ID
+	case side == domain.OSide && g.OPlayer != nil:
+		return g.OPlayer.ID
+	default:
+		return domain.PlayerID{}
+	}
+}
+
+func (uc *GameUC) getStartedGameWithSide(ctx context.Context, gameID uuid.UUID, playerID domain.PlayerID) (*domain.Game, domain.Side, error) {
+	g, err := uc.gameRepo.GetGame(ctx, gameID)
+	if err != nil {
+		return nil, domain.NoneSide, err
+	}
+
+	if g.State == domain.Created {
+		return nil, domain.NoneSide, &domain.GameErrorWithID{Err: domain.ErrGameNotStarted, ID: g.ID}
+	}
+	if g.State == domain.Finished {
+		return nil, domain.NoneSide, &domain.GameErrorWithID{Err: domain.ErrGameFinished, ID: g.ID}
+	}
+
+	switch {
+	case g.XPlayer != nil && g.XPlayer.ID == playerID:
+		return g, domain.XSide, nil
+	case g.OPlayer != nil && g.OPlayer.ID == playerID:
+		return g, domain.OSide, nil
+	default:
+		return nil, domain.NoneSide, &domain.PlayerError{Err: domain.ErrPlayerIsNotInGame, PlayerID: playerID}
+	}
+}
+
+func (uc *GameUC) GetSeries(ctx context.Context, seriesID uuid.UUID) (*domain.Series, error) {
+	return uc.seriesRepo.GetSeries(ctx, seriesID)
+}
+
+// recordSeriesGame counts the finished game in its series and starts the next
+// series game with swapped sides. It returns uuid.Nil if there is no next game.
+func (uc *GameUC) recordSeriesGame(ctx context.Context, g *domain.Game) (uuid.UUID, error) {
+	if g.SeriesID == uuid.Nil {
+		return uuid.Nil, nil
+	}
+
+	series, err := uc.seriesRepo.GetSeries(ctx, g.SeriesID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	err = series.RecordGame(g)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if series.State == domain.Finished {
+		return uuid.Nil, uc.seriesRepo.UpdateSeries(ctx, series)
+	}
+
+	next, err := uc.gameRepo.CreateGame(ctx, g.OPlayer.ID, domain.XSide, g.Mode, g.Config)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	err = uc.gameRepo.AddGamePlayer(ctx, next.ID, g.XPlayer.ID, domain.OSide)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	next.SeriesID = series.ID
+	next.Rated = g.Rated
+	next.State = domain.Started
+	err = uc.gameRepo.UpdateGame(ctx, next)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	series.GameIDs = append(series.GameIDs, next.ID)
+	err = uc.seriesRepo.UpdateSeries(ctx, series)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	uc.log.Debug("next series game is started",
+		slog.String("series_id", series.ID.String()),
+		slog.String("game_id", next.ID.String()),
+	)
+
+	return next.ID, nil
+}
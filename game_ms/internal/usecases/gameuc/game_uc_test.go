@@ -0,0 +1,338 @@
+package gameuc
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/adapters/mapstore"
+	"dataxo-backend-game-ms/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+var (
+	alice = domain.PlayerID{ClientID: "alice"}
+	bob   = domain.PlayerID{ClientID: "bob"}
+)
+
+type configGameMode struct {
+	GameMode
+}
+
+func (configGameMode) GetConfig() domain.DisappearingModeConfig {
+	return domain.DisappearingModeConfig{PlayerFiguresLimit: 6, WinLineLength: 4, BoardWidth: 4, BoardHeight: 4}
+}
+
+// IterateGame places the move without any rules.
+func (configGameMode) IterateGame(ctx context.Context, g *domain.Game, move domain.Move) (domain.MakeMoveResult, error) {
+	move.InGameID = len(g.Moves) + 1
+	g.Moves = append(g.Moves, move)
+	return domain.MakeMoveResult{Events: []domain.MoveEvent{{Type: domain.PlaceMove, Move: move}}}, nil
+}
+
+// UndoLastMove removes the last move.
+func (configGameMode) UndoLastMove(ctx context.Context, g *domain.Game) (domain.MakeMoveResult, error) {
+	move := g.Moves[len(g.Moves)-1]
+	g.Moves = g.Moves[:len(g.Moves)-1]
+	return domain.MakeMoveResult{Events: []domain.MoveEvent{{Type: domain.RemoveMove, Move: move}}}, nil
+}
+
+// newStartedGame returns the use case with the started game of alice as X and bob as O.
+func newStartedGame(t *testing.T) (*GameUC, *domain.Game) {
+	ctx := context.Background()
+	uc := New(mapstore.NewGameRepo(), mapstore.NewSeriesRepo(), configGameMode{}, nil)
+
+	g, err := uc.CreateGame(ctx, alice, domain.ModeWithFriend, domain.ModeParams{MySide: domain.XSideRequest})
+	require.NoError(t, err)
+
+	_, err = uc.JoinGame(ctx, g.ID, bob)
+	require.NoError(t, err)
+	require.NoError(t, uc.StartGame(ctx, g.ID))
+
+	return uc, g
+}
+
+func TestGameUC_AcceptDraw(t *testing.T) {
+	ctx := context.Background()
+	uc, g := newStartedGame(t)
+
+	side, err := uc.OfferDraw(ctx, g.ID, alice)
+	require.NoError(t, err)
+	assert.Equal(t, domain.XSide, side)
+
+	_, err = uc.AnswerDraw(ctx, g.ID, alice, true)
+	assert.ErrorIs(t, err, domain.ErrCantAnswerOwnDrawOffer)
+
+	res, err := uc.AnswerDraw(ctx, g.ID, bob, true)
+	require.NoError(t, err)
+	assert.True(t, res.GameFinished)
+	assert.Equal(t, domain.Draw, res.Winner)
+	assert.Equal(t, domain.DrawAgreementFinish, res.FinishReason)
+
+	g, err = uc.GetGame(ctx, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Finished, g.State)
+	assert.Equal(t, domain.NoneSide, g.DrawOfferedBy)
+}
+
+func TestGameUC_DeclineDraw(t *testing.T) {
+	ctx := context.Background()
+	uc, g := newStartedGame(t)
+
+	_, err := uc.OfferDraw(ctx, g.ID, bob)
+	require.NoError(t, err)
+
+	res, err := uc.AnswerDraw(ctx, g.ID, alice, false)
+	require.NoError(t, err)
+	assert.False(t, res.GameFinished)
+
+	g, err = uc.GetGame(ctx, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Started, g.State)
+	assert.Equal(t, domain.NoneSide, g.DrawOfferedBy)
+
+	_, err = uc.AnswerDraw(ctx, g.ID, alice, true)
+	assert.ErrorIs(t, err, domain.ErrDrawNotOffered, "declined offer can't be accepted")
+}
+
+func TestGameUC_RepeatedDrawOffer(t *testing.T) {
+	ctx := context.Background()
+	uc, g := newStartedGame(t)
+
+	_, err := uc.OfferDraw(ctx, g.ID, alice)
+	require.NoError(t, err)
+
+	_, err = uc.OfferDraw(ctx, g.ID, alice)
+	assert.ErrorIs(t, err, domain.ErrDrawAlreadyOffered)
+	_, err = uc.OfferDraw(ctx, g.ID, bob)
+	assert.ErrorIs(t, err, domain.ErrDrawAlreadyOffered, "the opponent should answer instead")
+
+	_, err = uc.OfferDraw(ctx, g.ID, domain.PlayerID{ClientID: "carol"})
+	assert.ErrorIs(t, err, domain.ErrPlayerIsNotInGame)
+}
+
+func TestGameUC_Resign(t *testing.T) {
+	ctx := context.Background()
+	uc, g := newStartedGame(t)
+
+	res, err := uc.Resign(ctx, g.ID, alice)
+	require.NoError(t, err)
+	assert.True(t, res.GameFinished)
+	assert.Equal(t, domain.OWin, res.Winner)
+	assert.Equal(t, domain.ResignationFinish, res.FinishReason)
+
+	_, err = uc.Resign(ctx, g.ID, bob)
+	assert.ErrorIs(t, err, domain.ErrGameFinished)
+	_, err = uc.OfferDraw(ctx, g.ID, bob)
+	assert.ErrorIs(t, err, domain.ErrGameFinished)
+}
+
+func TestGameUC_AcceptTakeback(t *testing.T) {
+	ctx := context.Background()
+	uc, g := newStartedGame(t)
+
+	_, err := uc.MakeMove(ctx, g.ID, domain.Move{X: 1, Y: 2, Side: domain.XSide})
+	require.NoError(t, err)
+
+	side, err := uc.ProposeTakeback(ctx, g.ID, alice)
+	require.NoError(t, err)
+	assert.Equal(t, domain.XSide, side)
+
+	_, err = uc.AnswerTakeback(ctx, g.ID, alice, true)
+	assert.ErrorIs(t, err, domain.ErrCantAnswerOwnTakeback)
+
+	res, err := uc.AnswerTakeback(ctx, g.ID, bob, true)
+	require.NoError(t, err)
+	require.Len(t, res.Events, 1)
+	assert.Equal(t, domain.RemoveMove, res.Events[0].Type)
+
+	g, err = uc.GetGame(ctx, g.ID)
+	require.NoError(t, err)
+	assert.Empty(t, g.Moves)
+	assert.Equal(t, domain.NoneSide, g.TakebackProposedBy)
+
+	history, err := uc.GetHistory(ctx, g.ID)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, domain.RemoveMove, history[1].Type)
+	assert.Equal(t, alice, history[1].Actor, "the taken back move is of the proposer")
+	assert.True(t, history[1].Takeback)
+	assert.Equal(t, 1, history[1].MoveNumber)
+}
+
+func TestGameUC_RejectTakeback(t *testing.T) {
+	ctx := context.Background()
+	uc, g := newStartedGame(t)
+
+	_, err := uc.MakeMove(ctx, g.ID, domain.Move{X: 1, Y: 2, Side: domain.XSide})
+	require.NoError(t, err)
+	_, err = uc.ProposeTakeback(ctx, g.ID, alice)
+	require.NoError(t, err)
+
+	res, err := uc.AnswerTakeback(ctx, g.ID, bob, false)
+	require.NoError(t, err)
+	assert.Empty(t, res.Events)
+
+	g, err = uc.GetGame(ctx, g.ID)
+	require.NoError(t, err)
+	assert.Len(t, g.Moves, 1)
+	assert.Equal(t, domain.NoneSide, g.TakebackProposedBy)
+
+	_, err = uc.AnswerTakeback(ctx, g.ID, bob, true)
+	assert.ErrorIs(t, err, domain.ErrTakebackNotProposed, "rejected proposal can't be accepted")
+
+	history, err := uc.GetHistory(ctx, g.ID)
+	require.NoError(t, err)
+	assert.Len(t, history, 1, "the rejection isn't logged")
+}
+
+func TestGameUC_ProposeTakeback(t *testing.T) {
+	ctx := context.Background()
+	uc, g := newStartedGame(t)
+
+	_, err := uc.ProposeTakeback(ctx, g.ID, alice)
+	assert.ErrorIs(t, err, domain.ErrNoMoveToTakeback, "empty game")
+
+	_, err = uc.MakeMove(ctx, g.ID, domain.Move{X: 1, Y: 2, Side: domain.XSide})
+	require.NoError(t, err)
+
+	_, err = uc.ProposeTakeback(ctx, g.ID, bob)
+	assert.ErrorIs(t, err, domain.ErrLastMoveIsNotYours)
+
+	_, err = uc.ProposeTakeback(ctx, g.ID, alice)
+	require.NoError(t, err)
+	_, err = uc.ProposeTakeback(ctx, g.ID, alice)
+	assert.ErrorIs(t, err, domain.ErrTakebackAlreadyProposed)
+
+	_, err = uc.Resign(ctx, g.ID, bob)
+	require.NoError(t, err)
+
+	_, err = uc.ProposeTakeback(ctx, g.ID, alice)
+	assert.ErrorIs(t, err, domain.ErrGameFinished)
+	_, err = uc.AnswerTakeback(ctx, g.ID, bob, true)
+	assert.ErrorIs(t, err, domain.ErrGameFinished)
+
+	g, err = uc.GetGame(ctx, g.ID)
+	require.NoError(t, err)
+	assert.Len(t, g.Moves, 1, "the finished game isn't changed")
+	assert.Equal(t, domain.NoneSide, g.TakebackProposedBy, "the finish drops the proposal")
+}
+
+func TestGameUC_FinishWithSeriesFailure(t *testing.T) {
+	ctx := context.Background()
+	uc, g := newStartedGame(t)
+
+	// the series is missing in the store
+	g.SeriesID = uuid.New()
+	require.NoError(t, uc.gameRepo.UpdateGame(ctx, g))
+
+	res, err := uc.Resign(ctx, g.ID, alice)
+	require.NoError(t, err, "the saved result isn't failed by the series")
+	assert.True(t, res.GameFinished)
+	assert.Equal(t, domain.OWin, res.Winner)
+	assert.Equal(t, uuid.Nil, res.NextGameID)
+
+	g, err = uc.GetGame(ctx, g.ID)
+	require.NoError(t, err)
+	assert.Equal(t, domain.Finished, g.State)
+}
+
+func TestGameUC_HistoryOfFinishActions(t *testing.T) {
+	ctx := context.Background()
+
+	tcases := []struct {
+		Name   string
+		Finish func(uc *GameUC, g *domain.Game) error
+		Kind   domain.HistoryEventKind
+		Actor  domain.PlayerID
+	}{
+		{
+			Name: "draw agreement is logged for the accepting player",
+			Finish: func(uc *GameUC, g *domain.Game) error {
+				if _, err := uc.OfferDraw(ctx, g.ID, alice); err != nil {
+					return err
+				}
+				_, err := uc.AnswerDraw(ctx, g.ID, bob, true)
+				return err
+			},
+			Kind:  domain.DrawAgreementHistoryEvent,
+			Actor: bob,
+		},
+		{
+			Name: "resignation is logged for the resigning player",
+			Finish: func(uc *GameUC, g *domain.Game) error {
+				_, err := uc.Resign(ctx, g.ID, bob)
+				return err
+			},
+			Kind:  domain.ResignationHistoryEvent,
+			Actor: bob,
+		},
+	}
+
+	for _, tc := range tcases {
+		uc, g := newStartedGame(t)
+
+		_, err := uc.MakeMove(ctx, g.ID, domain.Move{X: 1, Y: 2, Side: domain.XSide})
+		require.NoError(t, err, tc.Name)
+		require.NoError(t, tc.Finish(uc, g), tc.Name)
+
+		history, err := uc.GetHistory(ctx, g.ID)
+		require.NoError(t, err, tc.Name)
+		require.Len(t, history, 2, tc.Name)
+
+		assert.Equal(t, domain.MoveHistoryEvent, history[0].Kind, tc.Name)
+		assert.Equal(t, domain.PlaceMove, history[0].Type, tc.Name)
+		assert.Equal(t, alice, history[0].Actor, tc.Name)
+
+		assert.Equal(t, tc.Kind, history[1].Kind, tc.Name)
+		assert.Equal(t, domain.Move{}, history[1].Move, tc.Name, "the finish has no move")
+		assert.Equal(t, tc.Actor, history[1].Actor, tc.Name)
+		assert.Equal(t, 1, history[1].MoveNumber, tc.Name, "the number of the last move")
+	}
+}
+
+func TestGameUC_ImportGame(t *testing.T) {
+	ctx := context.Background()
+	uc := New(mapstore.NewGameRepo(), mapstore.NewSeriesRepo(), configGameMode{}, nil)
+
+	record := func(mode string) string {
+		return `[Mode "` + mode + `"]
+[BoardWidth "4"]
+[BoardHeight "4"]
+[WinLineLength "4"]
+[PlayerFiguresLimit "6"]
+[X "alice"]
+[O "bob"]
+[Result "1-0"]
+[Reason "resignation"]
+
+1. a1 b2 1-0
+`
+	}
+
+	_, err := uc.ImportGame(ctx, domain.PlayerID{ClientID: "carol"}, record(domain.ModeWithFriend))
+	assert.ErrorIs(t, err, domain.ErrPlayerIsNotInGame, "importer must be one of the players")
+
+	_, err = uc.ImportGame(ctx, alice, record("blitz"))
+	assert.ErrorIs(t, err, domain.ErrUnsupportedMode)
+
+	importer := domain.PlayerID{ClientID: "bob", UserID: uuid.New()}
+	g, err := uc.ImportGame(ctx, importer, record(domain.ModeWithFriend))
+	require.NoError(t, err)
+	assert.True(t, g.Imported)
+	assert.False(t, g.Rated)
+	assert.Equal(t, importer, g.OPlayer.ID, "importer is bound with the user id")
+	assert.Equal(t, domain.XWin, g.Winner)
+
+	_, total, err := uc.ListGames(ctx, domain.GameFilter{PlayerID: alice})
+	require.NoError(t, err)
+	assert.Zero(t, total, "imported games aren't listed by default")
+
+	_, total, err = uc.ListGames(ctx, domain.GameFilter{PlayerID: alice, Imported: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, total)
+
+	stats, err := uc.GetPlayerStats(ctx, alice)
+	require.NoError(t, err)
+	assert.Zero(t, stats, "imported games don't count in the stats")
+}
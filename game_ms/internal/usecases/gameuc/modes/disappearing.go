@@ -21,6 +21,7 @@ type WinChecker interface {
 
 type MoveMaker interface {
 	MakeMoveOnBoard(ctx context.Context, g *domain.Game, board gameuc.Board, move domain.Move) []domain.MoveEvent
+	UndoLastMove(ctx context.Context, g *domain.Game) []domain.MoveEvent
 }
 
 type DisappearingMode struct {
@@ -90,6 +91,10 @@ func (m *DisappearingMode) IterateGame(ctx context.Context, g *domain.Game, move
 		}, nil
 	case domain.XWin, domain.OWin, domain.Draw:
 		g.Winner = winResult.Side
+		g.FinishReason = domain.WinLineFinish
+		if winResult.Side == domain.Draw {
+			g.FinishReason = domain.NoPlaceFinish
+		}
 		g.State = domain.Finished
 		g.WinSequence = winResult.Sequence
 		return domain.MakeMoveResult{
@@ -102,6 +107,79 @@ func (m *DisappearingMode) IterateGame(ctx context.Context, g *domain.Game, move
 	}
 }
 
+// UndoLastMove takes back the last move and restores the figure
+// that has disappeared because of it.
+func (m *DisappearingMode) UndoLastMove(ctx context.Context, g *domain.Game) (domain.MakeMoveResult, error) {
+	if g == nil {
+		return domain.MakeMoveResult{}, domain.ErrGameIsNil
+	}
+
+	if g.State != domain.Started {
+		return domain.MakeMoveResult{}, &domain.GameErrorWithID{Err: domain.ErrGameNotStarted, ID: g.ID}
+	}
+	if len(g.Moves) == 0 {
+		return domain.MakeMoveResult{}, &domain.GameErrorWithID{Err: domain.ErrNoMoveToTakeback, ID: g.ID}
+	}
+
+	return domain.MakeMoveResult{
+		GameFinished: false,
+		Events:       m.moveMaker.UndoLastMove(ctx, g),
+	}, nil
+}
+
+// ReplayBoard rebuilds the board after the first ply moves of the game
+// by making them again on the empty board.
+func (m *DisappearingMode) ReplayBoard(ctx context.Context, g *domain.Game, ply int) (domain.BoardSnapshot, error) {
+	if g == nil {
+		return domain.BoardSnapshot{}, domain.ErrGameIsNil
+	}
+
+	if ply < 0 || ply > len(g.Moves) {
+		return domain.BoardSnapshot{}, &domain.GameErrorWithID{Err: domain.ErrInvalidPly, ID: g.ID}
+	}
+
+	replayed := &domain.Game{
+		ID:     g.ID,
+		Mode:   g.Mode,
+		Config: g.Config,
+		State:  domain.Started,
+		Moves:  make([]domain.Move, 0, ply),
+	}
+
+	for i := 0; i < ply; i++ {
+		move := g.Moves[i]
+		move.Side = domain.SideByInGameID(move.InGameID)
+
+		board := gameuc.NewBoard(replayed.Moves, m.Cfg.PlayerFiguresLimit)
+		m.moveMaker.MakeMoveOnBoard(ctx, replayed, board, move)
+	}
+
+	board := gameuc.NewBoard(replayed.Moves, m.Cfg.PlayerFiguresLimit)
+
+	snapshot := domain.BoardSnapshot{
+		Ply:      ply,
+		Cells:    make([]domain.Move, 0, len(board)),
+		NextSide: domain.SideByInGameID(ply),
+	}
+
+	for y := 0; y < m.Cfg.BoardHeight; y++ {
+		for x := 0; x < m.Cfg.BoardWidth; x++ {
+			move := board.GetMove(x, y)
+			if move.Side != domain.NoneSide {
+				snapshot.Cells = append(snapshot.Cells, move)
+			}
+		}
+	}
+
+	limit := m.Cfg.PlayerFiguresLimit * 2
+	if limit > 0 && ply-limit >= 0 {
+		next := replayed.Moves[ply-limit]
+		snapshot.NextToDisappear = &next
+	}
+
+	return snapshot, nil
+}
+
 func (m *DisappearingMode) GetConfig() domain.DisappearingModeConfig {
 	return m.Cfg
 }
@@ -0,0 +1,55 @@
+package modes
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestDisappearingMode_ReplayBoard(t *testing.T) {
+	ctx := context.Background()
+	mode, err := NewDisappearingMode(domain.DisappearingModeConfig{
+		PlayerFiguresLimit: 2,
+		WinLineLength:      3,
+		BoardWidth:         3,
+		BoardHeight:        3,
+	}, nil)
+	require.NoError(t, err)
+
+	g := &domain.Game{State: domain.Started}
+	coords := [][2]int{{0, 0}, {1, 1}, {0, 1}, {2, 2}, {2, 0}, {1, 0}}
+	for i, c := range coords {
+		_, err := mode.IterateGame(ctx, g, domain.Move{
+			InGameID: i,
+			X:        c[0],
+			Y:        c[1],
+			Side:     domain.SideByInGameID(i),
+		})
+		require.NoError(t, err)
+	}
+
+	snapshot, err := mode.ReplayBoard(ctx, g, 4)
+	require.NoError(t, err)
+	assert.Equal(t, domain.XSide, snapshot.NextSide)
+	assert.Len(t, snapshot.Cells, 4)
+	require.NotNil(t, snapshot.NextToDisappear)
+	assert.Equal(t, 0, snapshot.NextToDisappear.InGameID)
+
+	snapshot, err = mode.ReplayBoard(ctx, g, len(coords))
+	require.NoError(t, err)
+	assert.Len(t, snapshot.Cells, 4)
+	for _, cell := range snapshot.Cells {
+		assert.NotContains(t, []int{0, 1}, cell.InGameID)
+		assert.Equal(t, domain.SideByInGameID(cell.InGameID), cell.Side)
+	}
+
+	snapshot, err = mode.ReplayBoard(ctx, g, 0)
+	require.NoError(t, err)
+	assert.Empty(t, snapshot.Cells)
+	assert.Nil(t, snapshot.NextToDisappear)
+
+	_, err = mode.ReplayBoard(ctx, g, len(coords)+1)
+	assert.ErrorIs(t, err, domain.ErrInvalidPly)
+}
@@ -55,3 +55,38 @@ func (r *Default) MakeMoveOnBoard(ctx context.Context, g *domain.Game, board gam
 
 	return events
 }
+
+func (r *Default) UndoLastMove(ctx context.Context, g *domain.Game) []domain.MoveEvent {
+	if len(g.Moves) == 0 {
+		return nil
+	}
+
+	events := make([]domain.MoveEvent, 0, 2)
+
+	last := g.Moves[len(g.Moves)-1]
+	events = append(events, domain.MoveEvent{
+		Type: domain.RemoveMove,
+		Move: last,
+	})
+	g.Moves = g.Moves[:len(g.Moves)-1]
+
+	limit := r.Cfg.PlayerFiguresLimit * 2
+
+	if limit <= 0 {
+		return events
+	}
+
+	restoredIndex := len(g.Moves) - limit
+
+	if restoredIndex < 0 {
+		return events
+	}
+
+	g.Moves[restoredIndex].Side = domain.SideByInGameID(g.Moves[restoredIndex].InGameID)
+	events = append(events, domain.MoveEvent{
+		Type: domain.PlaceMove,
+		Move: g.Moves[restoredIndex],
+	})
+
+	return events
+}
@@ -0,0 +1,45 @@
+package movemakers
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/internal/usecases/gameuc"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDefaultMoveMaker_UndoLastMove(t *testing.T) {
+	ctx := context.Background()
+	cfg := domain.DisappearingModeConfig{
+		PlayerFiguresLimit: 1,
+		WinLineLength:      3,
+		BoardWidth:         3,
+		BoardHeight:        3,
+	}
+	moveMaker := NewDefault(cfg, nil)
+
+	g := &domain.Game{State: domain.Started}
+	moves := []domain.Move{
+		{InGameID: 0, X: 0, Y: 0, Side: domain.XSide},
+		{InGameID: 1, X: 1, Y: 0, Side: domain.OSide},
+		{InGameID: 2, X: 2, Y: 0, Side: domain.XSide},
+	}
+	for _, move := range moves {
+		board := gameuc.NewBoard(g.Moves, cfg.PlayerFiguresLimit)
+		moveMaker.MakeMoveOnBoard(ctx, g, board, move)
+	}
+	assert.Equal(t, domain.NoneSide, g.Moves[0].Side)
+
+	events := moveMaker.UndoLastMove(ctx, g)
+
+	assert.Len(t, g.Moves, 2)
+	assert.Equal(t, domain.XSide, g.Moves[0].Side)
+	assert.Equal(t, []domain.MoveEvent{
+		{Type: domain.RemoveMove, Move: domain.Move{InGameID: 2, X: 2, Y: 0, Side: domain.XSide, TimesUsed: 1}},
+		{Type: domain.PlaceMove, Move: domain.Move{InGameID: 0, X: 0, Y: 0, Side: domain.XSide, TimesUsed: 1}},
+	}, events)
+
+	events = moveMaker.UndoLastMove(ctx, g)
+	assert.Len(t, g.Moves, 1)
+	assert.Len(t, events, 1)
+}
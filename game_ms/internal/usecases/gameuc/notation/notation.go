@@ -0,0 +1,332 @@
+// Package notation implements a text notation of disappearing tic-tac-toe games.
+//
+// A record consists of a header with tag pairs and a numbered move list:
+//
+//	[Mode "with-friend"]
+//	[BoardWidth "4"]
+//	[BoardHeight "4"]
+//	[WinLineLength "4"]
+//	[PlayerFiguresLimit "6"]
+//	[X "first-client"]
+//	[O "second-client"]
+//	[Result "1-0"]
+//	[Reason "win_line"]
+//
+//	1. a1 b2 2. a2 b3 3. a3 b4 4. a4 1-0
+//
+// A move is a column letter starting from "a" and a row number starting from 1.
+// Results are "1-0" for X win, "0-1" for O win, "1/2-1/2" for draw and "*" for unfinished game.
+package notation
+
+import (
+	"bufio"
+	"dataxo-backend-game-ms/internal/domain"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidTag     = errors.New("invalid tag")
+	ErrUnknownTag     = errors.New("unknown tag")
+	ErrMissingTag     = errors.New("missing tag")
+	ErrInvalidMove    = errors.New("invalid move")
+	ErrInvalidResult  = errors.New("invalid result")
+	ErrInvalidReason  = errors.New("invalid finish reason")
+	ErrBoardTooWide   = errors.New("board is too wide for notation")
+	ErrUnexpectedText = errors.New("unexpected text after result")
+)
+
+type ParseError struct {
+	Err   error
+	Line  int
+	Token string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("line(%v) token(%v): %v", e.Line, e.Token, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+const maxBoardWidth = 'z' - 'a' + 1
+
+type Header struct {
+	Mode    string
+	Config  domain.DisappearingModeConfig
+	XPlayer domain.PlayerID
+	OPlayer domain.PlayerID
+	Result  domain.WinSide
+	Reason  domain.FinishReason
+}
+
+type Record struct {
+	Header Header
+	Moves  []domain.Move
+}
+
+func Export(g *domain.Game) (string, error) {
+	if g == nil {
+		return "", domain.ErrGameIsNil
+	}
+	if g.Config.BoardWidth > maxBoardWidth {
+		return "", ErrBoardTooWide
+	}
+
+	sb := &strings.Builder{}
+
+	writeTag(sb, "Mode", g.Mode)
+	writeTag(sb, "BoardWidth", strconv.Itoa(g.Config.BoardWidth))
+	writeTag(sb, "BoardHeight", strconv.Itoa(g.Config.BoardHeight))
+	writeTag(sb, "WinLineLength", strconv.Itoa(g.Config.WinLineLength))
+	writeTag(sb, "PlayerFiguresLimit", strconv.Itoa(g.Config.PlayerFiguresLimit))
+	if g.XPlayer != nil {
+		writeTag(sb, "X", g.XPlayer.ID.ClientID)
+	}
+	if g.OPlayer != nil {
+		writeTag(sb, "O", g.OPlayer.ID.ClientID)
+	}
+
+	result := FormatResult(g.Winner)
+	writeTag(sb, "Result", result)
+	if g.State == domain.Finished {
+		writeTag(sb, "Reason", g.FinishReason.String())
+	}
+
+	sb.WriteString("\n")
+
+	for i, move := range g.Moves {
+		if i%2 == 0 {
+			if i > 0 {
+				sb.WriteString(" ")
+			}
+			sb.WriteString(strconv.Itoa(i/2 + 1))
+			sb.WriteString(".")
+		}
+		sb.WriteString(" ")
+		sb.WriteString(FormatMove(move))
+	}
+
+	if len(g.Moves) > 0 {
+		sb.WriteString(" ")
+	}
+	sb.WriteString(result)
+	sb.WriteString("\n")
+
+	return sb.String(), nil
+}
+
+func Parse(text string) (Record, error) {
+	rec := Record{Moves: make([]domain.Move, 0)}
+	tags := make(map[string]string)
+
+	resultFound := false
+	var result domain.WinSide
+	scanner := bufio.NewScanner(strings.NewReader(text))
+
+	for line := 1; scanner.Scan(); line++ {
+		s := strings.TrimSpace(scanner.Text())
+		if s == "" {
+			continue
+		}
+
+		if strings.HasPrefix(s, "[") {
+			key, value, err := parseTag(s)
+			if err != nil {
+				return Record{}, &ParseError{Err: err, Line: line, Token: s}
+			}
+			tags[key] = value
+			continue
+		}
+
+		for _, token := range strings.Fields(s) {
+			if resultFound {
+				return Record{}, &ParseError{Err: ErrUnexpectedText, Line: line, Token: token}
+			}
+
+			if r, err := ParseResult(token); err == nil {
+				resultFound = true
+				result = r
+				continue
+			}
+
+			if strings.HasSuffix(token, ".") {
+				if _, err := strconv.Atoi(strings.TrimSuffix(token, ".")); err != nil {
+					return Record{}, &ParseError{Err: ErrInvalidMove, Line: line, Token: token}
+				}
+				continue
+			}
+
+			move, err := ParseMove(token)
+			if err != nil {
+				return Record{}, &ParseError{Err: err, Line: line, Token: token}
+			}
+
+			move.InGameID = len(rec.Moves)
+			move.Side = domain.SideByInGameID(move.InGameID)
+			rec.Moves = append(rec.Moves, move)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Record{}, err
+	}
+
+	header, err := parseHeader(tags)
+	if err != nil {
+		return Record{}, err
+	}
+	if resultFound && result != header.Result {
+		// the result after the moves must repeat the Result tag
+		return Record{}, &ParseError{Err: domain.ErrGameResultMismatch, Token: FormatResult(result)}
+	}
+	rec.Header = header
+
+	return rec, nil
+}
+
+func FormatMove(move domain.Move) string {
+	return fmt.Sprintf("%c%d", rune('a'+move.X), move.Y+1)
+}
+
+func ParseMove(token string) (domain.Move, error) {
+	if len(token) < 2 || token[0] < 'a' || token[0] > 'z' {
+		return domain.Move{}, ErrInvalidMove
+	}
+
+	row, err := strconv.Atoi(token[1:])
+	if err != nil || row < 1 {
+		return domain.Move{}, ErrInvalidMove
+	}
+
+	return domain.Move{X: int(token[0] - 'a'), Y: row - 1}, nil
+}
+
+func FormatResult(winner domain.WinSide) string {
+	switch winner {
+	case domain.XWin:
+		return "1-0"
+	case domain.OWin:
+		return "0-1"
+	case domain.Draw:
+		return "1/2-1/2"
+	default:
+		return "*"
+	}
+}
+
+func ParseResult(token string) (domain.WinSide, error) {
+	switch token {
+	case "1-0":
+		return domain.XWin, nil
+	case "0-1":
+		return domain.OWin, nil
+	case "1/2-1/2":
+		return domain.Draw, nil
+	case "*":
+		return domain.NoneWin, nil
+	default:
+		return domain.NoneWin, ErrInvalidResult
+	}
+}
+
+func ParseReason(s string) (domain.FinishReason, error) {
+	reasons := []domain.FinishReason{
+		domain.NotFinished,
+		domain.WinLineFinish,
+		domain.NoPlaceFinish,
+		domain.DrawAgreementFinish,
+		domain.ResignationFinish,
+	}
+
+	for _, reason := range reasons {
+		if reason.String() == s {
+			return reason, nil
+		}
+	}
+
+	return domain.NotFinished, ErrInvalidReason
+}
+
+func writeTag(sb *strings.Builder, key, value string) {
+	sb.WriteString(fmt.Sprintf("[%s %s]\n", key, strconv.Quote(value)))
+}
+
+func parseTag(s string) (string, string, error) {
+	if !strings.HasSuffix(s, "]") {
+		return "", "", ErrInvalidTag
+	}
+
+	key, rawValue, ok := strings.Cut(strings.TrimSpace(s[1:len(s)-1]), " ")
+	if !ok {
+		return "", "", ErrInvalidTag
+	}
+
+	value, err := strconv.Unquote(strings.TrimSpace(rawValue))
+	if err != nil {
+		return "", "", ErrInvalidTag
+	}
+
+	return key, value, nil
+}
+
+func parseHeader(tags map[string]string) (Header, error) {
+	header := Header{Reason: domain.NotFinished}
+
+	ints := map[string]*int{
+		"BoardWidth":         &header.Config.BoardWidth,
+		"BoardHeight":        &header.Config.BoardHeight,
+		"WinLineLength":      &header.Config.WinLineLength,
+		"PlayerFiguresLimit": &header.Config.PlayerFiguresLimit,
+	}
+
+	for key, value := range tags {
+		var err error
+
+		switch key {
+		case "Mode":
+			header.Mode = value
+		case "X":
+			header.XPlayer = domain.PlayerID{ClientID: value}
+		case "O":
+			header.OPlayer = domain.PlayerID{ClientID: value}
+		case "Result":
+			header.Result, err = ParseResult(value)
+		case "Reason":
+			header.Reason, err = ParseReason(value)
+		default:
+			dst, ok := ints[key]
+			if !ok {
+				return Header{}, &ParseError{Err: ErrUnknownTag, Token: key}
+			}
+
+			*dst, err = strconv.Atoi(value)
+			if err != nil {
+				err = ErrInvalidTag
+			}
+		}
+
+		if err != nil {
+			return Header{}, &ParseError{Err: err, Token: key}
+		}
+	}
+
+	for _, key := range []string{"Mode", "BoardWidth", "BoardHeight", "WinLineLength", "PlayerFiguresLimit"} {
+		if _, ok := tags[key]; !ok {
+			return Header{}, &ParseError{Err: ErrMissingTag, Token: key}
+		}
+	}
+
+	if header.Config.BoardWidth > maxBoardWidth {
+		return Header{}, ErrBoardTooWide
+	}
+
+	err := domain.ValidateDisappearingModeConfig(header.Config)
+	if err != nil {
+		return Header{}, err
+	}
+
+	return header, nil
+}
@@ -0,0 +1,102 @@
+package notation
+
+import (
+	"dataxo-backend-game-ms/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestExportParse(t *testing.T) {
+	g := &domain.Game{
+		Mode: domain.ModeWithFriend,
+		Config: domain.DisappearingModeConfig{
+			PlayerFiguresLimit: 6,
+			WinLineLength:      3,
+			BoardWidth:         3,
+			BoardHeight:        3,
+		},
+		State: domain.Finished,
+		Moves: []domain.Move{
+			{InGameID: 0, X: 0, Y: 0, Side: domain.XSide},
+			{InGameID: 1, X: 1, Y: 1, Side: domain.OSide},
+			{InGameID: 2, X: 0, Y: 1, Side: domain.XSide},
+			{InGameID: 3, X: 2, Y: 2, Side: domain.OSide},
+			{InGameID: 4, X: 0, Y: 2, Side: domain.XSide},
+		},
+		XPlayer:      &domain.Player{ID: domain.PlayerID{ClientID: "alice"}},
+		OPlayer:      &domain.Player{ID: domain.PlayerID{ClientID: "bob"}},
+		Winner:       domain.XWin,
+		FinishReason: domain.WinLineFinish,
+	}
+
+	text, err := Export(g)
+	require.NoError(t, err)
+	assert.Contains(t, text, "1. a1 b2 2. a2 c3 3. a3 1-0\n")
+
+	rec, err := Parse(text)
+	require.NoError(t, err)
+	assert.Equal(t, g.Mode, rec.Header.Mode)
+	assert.Equal(t, g.Config, rec.Header.Config)
+	assert.Equal(t, g.XPlayer.ID, rec.Header.XPlayer)
+	assert.Equal(t, g.OPlayer.ID, rec.Header.OPlayer)
+	assert.Equal(t, g.Winner, rec.Header.Result)
+	assert.Equal(t, g.FinishReason, rec.Header.Reason)
+	assert.Equal(t, g.Moves, rec.Moves)
+}
+
+func TestParseErrors(t *testing.T) {
+	header := `[Mode "with-friend"]
+[BoardWidth "3"]
+[BoardHeight "3"]
+[WinLineLength "3"]
+[PlayerFiguresLimit "0"]
+`
+
+	tcases := []struct {
+		Name string
+		Text string
+		Err  error
+	}{
+		{
+			Name: "invalid move",
+			Text: header + "1. a1 9z *",
+			Err:  ErrInvalidMove,
+		},
+		{
+			Name: "text after result",
+			Text: header + "1. a1 * b2",
+			Err:  ErrUnexpectedText,
+		},
+		{
+			Name: "result after moves differs from the tag",
+			Text: header + `[Result "0-1"]` + "\n1. a1 1-0",
+			Err:  domain.ErrGameResultMismatch,
+		},
+		{
+			Name: "unknown tag",
+			Text: header + `[Event "cup"]` + "\n*",
+			Err:  ErrUnknownTag,
+		},
+		{
+			Name: "missing tag",
+			Text: `[Mode "with-friend"]` + "\n*",
+			Err:  ErrMissingTag,
+		},
+		{
+			Name: "invalid config",
+			Text: `[Mode "with-friend"]
+[BoardWidth "3"]
+[BoardHeight "0"]
+[WinLineLength "3"]
+[PlayerFiguresLimit "0"]
+*`,
+			Err: domain.ErrNegativeOrZeroedBoardHeight,
+		},
+	}
+
+	for _, tc := range tcases {
+		_, err := Parse(tc.Text)
+		assert.ErrorIs(t, err, tc.Err, tc.Name)
+	}
+}
@@ -0,0 +1,145 @@
+package ratinguc
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/pkg/slogdiscard"
+	"errors"
+	"github.com/google/uuid"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type RatingRepository interface {
+	GetRating(ctx context.Context, playerID domain.PlayerID, pool string) (domain.Rating, error)
+	ListRatings(ctx context.Context, playerID domain.PlayerID) ([]domain.Rating, error)
+	SaveRatings(ctx context.Context, ratings ...domain.Rating) error
+	// TopRatings returns the ratings of the pool in the order of the leaderboard,
+	// the order must be maintained on save rather than computed on read.
+	TopRatings(ctx context.Context, pool string, leaderboard domain.Leaderboard, limit, offset int) ([]domain.Rating, error)
+}
+
+// UserRepository gives the users of the players, guests aren't rated.
+type UserRepository interface {
+	GetUser(ctx context.Context, userID uuid.UUID) (*domain.User, error)
+}
+
+type RatingUC struct {
+	ratingRepo RatingRepository
+	userRepo   UserRepository
+	log        *slog.Logger
+	// serializes read-modify-write of the ratings
+	mu sync.Mutex
+}
+
+func New(ratingRepo RatingRepository, userRepo UserRepository, log *slog.Logger) *RatingUC {
+	return &RatingUC{ratingRepo: ratingRepo, userRepo: userRepo, log: slogdiscard.LoggerIfNil(log)}
+}
+
+// RateGame updates the ratings of the players by the finished game result.
+// It returns nil changes if the game isn't ratable or any of the players is a guest,
+// otherwise guests created on demand would farm the rating.
+func (uc *RatingUC) RateGame(ctx context.Context, g *domain.Game) ([]domain.RatingChange, error) {
+	if !g.IsRatable() {
+		return nil, nil
+	}
+
+	registered, err := uc.areRegistered(ctx, g.XPlayer.ID, g.OPlayer.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !registered {
+		uc.log.Debug("game of the guest isn't rated", slog.String("game_id", g.ID.String()))
+		return nil, nil
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	pool := domain.RatingPool(g.Mode, g.Config)
+
+	x, err := uc.getRating(ctx, g.XPlayer.ID, pool)
+	if err != nil {
+		return nil, err
+	}
+	o, err := uc.getRating(ctx, g.OPlayer.ID, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	var xScore float64
+	switch g.Winner {
+	case domain.XWin:
+		xScore = 1
+	case domain.Draw:
+		xScore = 0.5
+	}
+
+	now := time.Now()
+	xValue := x.Value
+	changes := []domain.RatingChange{
+		x.Apply(o.Value, xScore, now),
+		o.Apply(xValue, 1-xScore, now),
+	}
+
+	err = uc.ratingRepo.SaveRatings(ctx, x, o)
+	if err != nil {
+		return nil, err
+	}
+
+	uc.log.Debug("game is rated",
+		slog.String("game_id", g.ID.String()),
+		slog.Any("changes", changes),
+	)
+
+	return changes, nil
+}
+
+func (uc *RatingUC) GetRatings(ctx context.Context, playerID domain.PlayerID) ([]domain.Rating, error) {
+	return uc.ratingRepo.ListRatings(ctx, playerID)
+}
+
+// GetLeaderboard returns the ranked ratings of the pool.
+func (uc *RatingUC) GetLeaderboard(ctx context.Context, pool string, leaderboard domain.Leaderboard, limit, offset int) ([]domain.LeaderboardEntry, error) {
+	if pool == "" {
+		return nil, domain.ErrMissingRatingPool
+	}
+
+	ratings, err := uc.ratingRepo.TopRatings(ctx, pool, leaderboard, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]domain.LeaderboardEntry, len(ratings))
+	for i := range ratings {
+		entries[i] = domain.LeaderboardEntry{Rank: offset + i + 1, Rating: ratings[i]}
+	}
+
+	return entries, nil
+}
+
+func (uc *RatingUC) areRegistered(ctx context.Context, playerIDs ...domain.PlayerID) (bool, error) {
+	for _, playerID := range playerIDs {
+		u, err := uc.userRepo.GetUser(ctx, playerID.UserID)
+		if errors.Is(err, domain.ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		if u.IsGuest() {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (uc *RatingUC) getRating(ctx context.Context, playerID domain.PlayerID, pool string) (domain.Rating, error) {
+	rating, err := uc.ratingRepo.GetRating(ctx, playerID, pool)
+	if errors.Is(err, domain.ErrNotFound) {
+		return domain.NewRating(playerID, pool), nil
+	}
+	return rating, err
+}
@@ -0,0 +1,134 @@
+package ratinguc
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/adapters/mapstore"
+	"dataxo-backend-game-ms/internal/domain"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// newRatingUC returns the use case and the function that creates the player of the user.
+func newRatingUC(t *testing.T) (*RatingUC, func(guest bool) domain.PlayerID) {
+	userRepo := mapstore.NewUserRepo()
+
+	newPlayer := func(guest bool) domain.PlayerID {
+		u := &domain.User{ID: uuid.New(), DisplayName: "Player"}
+		if !guest {
+			u.Username = "user-" + u.ID.String()[:8]
+		}
+		require.NoError(t, userRepo.CreateUser(context.Background(), u))
+		return u.PlayerID()
+	}
+
+	return New(mapstore.NewRatingRepo(), userRepo, nil), newPlayer
+}
+
+func TestRatingUC_RateGame(t *testing.T) {
+	ctx := context.Background()
+	uc, newPlayer := newRatingUC(t)
+
+	x := newPlayer(false)
+	o := newPlayer(false)
+	cfg := domain.DisappearingModeConfig{PlayerFiguresLimit: 6, WinLineLength: 4, BoardWidth: 4, BoardHeight: 4}
+	g := &domain.Game{ID: uuid.New(), Mode: domain.ModeWithFriend, Config: cfg, State: domain.Finished,
+		XPlayer: &domain.Player{ID: x}, OPlayer: &domain.Player{ID: o}, Winner: domain.OWin}
+
+	changes, err := uc.RateGame(ctx, g)
+	require.NoError(t, err)
+	assert.Nil(t, changes, "unrated game")
+
+	g.Rated = true
+	changes, err = uc.RateGame(ctx, g)
+	require.NoError(t, err)
+	require.Len(t, changes, 2)
+	assert.Equal(t, x, changes[0].PlayerID)
+	assert.Equal(t, -20, changes[0].Delta())
+	assert.Equal(t, 20, changes[1].Delta())
+
+	g.Winner = domain.Draw
+	changes, err = uc.RateGame(ctx, g)
+	require.NoError(t, err)
+	assert.Positive(t, changes[0].Delta(), "weaker player gains on draw")
+	assert.Equal(t, -changes[0].Delta(), changes[1].Delta())
+
+	ratings, err := uc.GetRatings(ctx, o)
+	require.NoError(t, err)
+	require.Len(t, ratings, 1)
+	assert.Equal(t, domain.RatingPool(domain.ModeWithFriend, cfg), ratings[0].Pool)
+	assert.Equal(t, changes[1].After, ratings[0].Value)
+	assert.Equal(t, 2, ratings[0].Games)
+	assert.Equal(t, 1, ratings[0].Wins)
+	assert.Equal(t, 1, ratings[0].Draws)
+}
+
+func TestRatingUC_RateGameOfGuest(t *testing.T) {
+	ctx := context.Background()
+	uc, newPlayer := newRatingUC(t)
+
+	user := newPlayer(false)
+	cfg := domain.DisappearingModeConfig{PlayerFiguresLimit: 6, WinLineLength: 4, BoardWidth: 4, BoardHeight: 4}
+
+	for name, opponent := range map[string]domain.PlayerID{
+		"guest":   newPlayer(true),
+		"unknown": domain.UserPlayerID(uuid.New()),
+	} {
+		changes, err := uc.RateGame(ctx, &domain.Game{ID: uuid.New(), Mode: domain.ModeWithFriend, Config: cfg,
+			State: domain.Finished, Rated: true, Winner: domain.XWin,
+			XPlayer: &domain.Player{ID: user}, OPlayer: &domain.Player{ID: opponent}})
+		require.NoError(t, err, name)
+		assert.Nil(t, changes, name)
+	}
+
+	ratings, err := uc.GetRatings(ctx, user)
+	require.NoError(t, err)
+	assert.Empty(t, ratings, "rating isn't farmed with guests")
+}
+
+func TestRatingUC_GetLeaderboard(t *testing.T) {
+	ctx := context.Background()
+	uc, newPlayer := newRatingUC(t)
+
+	cfg := domain.DisappearingModeConfig{PlayerFiguresLimit: 6, WinLineLength: 4, BoardWidth: 4, BoardHeight: 4}
+	pool := domain.RatingPool(domain.ModeWithFriend, cfg)
+
+	players := []domain.PlayerID{
+		newPlayer(false), newPlayer(false), newPlayer(false),
+	}
+	play := func(x, o int, winner domain.WinSide) {
+		_, err := uc.RateGame(ctx, &domain.Game{ID: uuid.New(), Mode: domain.ModeWithFriend, Config: cfg,
+			State: domain.Finished, Rated: true, Winner: winner,
+			XPlayer: &domain.Player{ID: players[x]}, OPlayer: &domain.Player{ID: players[o]}})
+		require.NoError(t, err)
+	}
+
+	play(0, 1, domain.XWin)
+	play(0, 2, domain.XWin)
+	play(1, 0, domain.XWin)
+	play(2, 1, domain.XWin)
+	play(2, 0, domain.XWin)
+
+	ranked := func(leaderboard domain.Leaderboard, limit, offset int) []domain.PlayerID {
+		entries, err := uc.GetLeaderboard(ctx, pool, leaderboard, limit, offset)
+		require.NoError(t, err)
+
+		ids := make([]domain.PlayerID, len(entries))
+		for i := range entries {
+			assert.Equal(t, offset+i+1, entries[i].Rank)
+			ids[i] = entries[i].Rating.PlayerID
+		}
+		return ids
+	}
+
+	assert.Equal(t, []domain.PlayerID{players[2], players[0], players[1]}, ranked(domain.RatingLeaderboard, 10, 0))
+	assert.Equal(t, []domain.PlayerID{players[2], players[0]}, ranked(domain.WinsLeaderboard, 2, 0))
+	assert.Equal(t, []domain.PlayerID{players[1]}, ranked(domain.WinsLeaderboard, 2, 2))
+	assert.Equal(t, []domain.PlayerID{players[2], players[0], players[1]}, ranked(domain.WinStreakLeaderboard, 10, 0),
+		"equal streaks are ordered by rating")
+	assert.Empty(t, ranked(domain.RatingLeaderboard, 10, 3))
+
+	_, err := uc.GetLeaderboard(ctx, "", domain.RatingLeaderboard, 10, 0)
+	assert.ErrorIs(t, err, domain.ErrMissingRatingPool)
+}
@@ -0,0 +1,142 @@
+package useruc
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/domain"
+	"dataxo-backend-game-ms/pkg/slogdiscard"
+	"errors"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+type UserRepository interface {
+	CreateUser(ctx context.Context, u *domain.User) error
+	GetUser(ctx context.Context, userID uuid.UUID) (*domain.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*domain.User, error)
+	// UpdateUser returns domain.ErrUsernameTaken if other user has the username.
+	UpdateUser(ctx context.Context, u *domain.User) error
+}
+
+type UserUC struct {
+	userRepo   UserRepository
+	log        *slog.Logger
+	bcryptCost int
+	// compared with the password of unknown username to take the same time
+	dummyHash []byte
+}
+
+func New(userRepo UserRepository, log *slog.Logger) *UserUC {
+	uc := &UserUC{userRepo: userRepo, log: slogdiscard.LoggerIfNil(log), bcryptCost: bcrypt.DefaultCost}
+	uc.dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), uc.bcryptCost)
+	return uc
+}
+
+func (uc *UserUC) CreateGuest(ctx context.Context) (*domain.User, error) {
+	id, err := uuid.NewRandom()
+	if err != nil {
+		return nil, err
+	}
+
+	u := &domain.User{
+		ID:          id,
+		DisplayName: domain.GuestDisplayName(id),
+		CreatedAt:   time.Now(),
+	}
+
+	err = uc.userRepo.CreateUser(ctx, u)
+	if err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+func (uc *UserUC) GetUser(ctx context.Context, userID uuid.UUID) (*domain.User, error) {
+	return uc.userRepo.GetUser(ctx, userID)
+}
+
+// Register upgrades the guest to the registered account keeping its id,
+// so the games of the guest stay with the account.
+func (uc *UserUC) Register(ctx context.Context, userID uuid.UUID, username, password string) (*domain.User, error) {
+	username = domain.NormalizeUsername(username)
+	if err := domain.ValidateUsername(username); err != nil {
+		return nil, err
+	}
+	if err := domain.ValidatePassword(password); err != nil {
+		return nil, err
+	}
+
+	u, err := uc.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !u.IsGuest() {
+		return nil, &domain.UserError{Err: domain.ErrAlreadyRegistered, ID: userID}
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), uc.bcryptCost)
+	if err != nil {
+		return nil, err
+	}
+
+	registered := *u
+	registered.Username = username
+	registered.PasswordHash = hash
+	registered.RegisteredAt = time.Now()
+	if u.DisplayName == domain.GuestDisplayName(u.ID) {
+		registered.DisplayName = username
+	}
+
+	err = uc.userRepo.UpdateUser(ctx, &registered)
+	if err != nil {
+		return nil, err
+	}
+
+	return &registered, nil
+}
+
+// Login returns the user with the username and password.
+// The error is domain.ErrInvalidCredentials whatever is wrong.
+func (uc *UserUC) Login(ctx context.Context, username, password string) (*domain.User, error) {
+	u, err := uc.userRepo.GetUserByUsername(ctx, domain.NormalizeUsername(username))
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			return nil, err
+		}
+
+		_ = bcrypt.CompareHashAndPassword(uc.dummyHash, []byte(password))
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	err = bcrypt.CompareHashAndPassword(u.PasswordHash, []byte(password))
+	if err != nil {
+		return nil, domain.ErrInvalidCredentials
+	}
+
+	return u, nil
+}
+
+func (uc *UserUC) UpdateProfile(ctx context.Context, userID uuid.UUID, displayName string) (*domain.User, error) {
+	displayName = strings.TrimSpace(displayName)
+	if err := domain.ValidateDisplayName(displayName); err != nil {
+		return nil, err
+	}
+
+	u, err := uc.userRepo.GetUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := *u
+	updated.DisplayName = displayName
+
+	err = uc.userRepo.UpdateUser(ctx, &updated)
+	if err != nil {
+		return nil, err
+	}
+
+	return &updated, nil
+}
@@ -0,0 +1,75 @@
+package useruc
+
+import (
+	"context"
+	"dataxo-backend-game-ms/internal/adapters/mapstore"
+	"dataxo-backend-game-ms/internal/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"testing"
+)
+
+func newTestUC() *UserUC {
+	uc := New(mapstore.NewUserRepo(), nil)
+	uc.bcryptCost = bcrypt.MinCost
+	return uc
+}
+
+func TestUserUC_RegisterLogin(t *testing.T) {
+	ctx := context.Background()
+	uc := newTestUC()
+
+	guest, err := uc.CreateGuest(ctx)
+	require.NoError(t, err)
+	assert.True(t, guest.IsGuest())
+	assert.Equal(t, domain.GuestDisplayName(guest.ID), guest.DisplayName)
+
+	_, err = uc.Register(ctx, guest.ID, "al", "password")
+	assert.ErrorIs(t, err, domain.ErrInvalidUsername)
+	_, err = uc.Register(ctx, guest.ID, "alice", "short")
+	assert.ErrorIs(t, err, domain.ErrWeakPassword)
+
+	u, err := uc.Register(ctx, guest.ID, " Alice ", "password")
+	require.NoError(t, err)
+	assert.Equal(t, guest.ID, u.ID, "guest keeps its id")
+	assert.Equal(t, "alice", u.Username)
+	assert.Equal(t, "alice", u.DisplayName)
+	assert.False(t, u.RegisteredAt.IsZero())
+
+	_, err = uc.Register(ctx, guest.ID, "alice2", "password")
+	assert.ErrorIs(t, err, domain.ErrAlreadyRegistered)
+
+	other, err := uc.CreateGuest(ctx)
+	require.NoError(t, err)
+	_, err = uc.Register(ctx, other.ID, "ALICE", "password")
+	assert.ErrorIs(t, err, domain.ErrUsernameTaken)
+
+	logged, err := uc.Login(ctx, "ALICE", "password")
+	require.NoError(t, err)
+	assert.Equal(t, guest.ID, logged.ID)
+
+	_, err = uc.Login(ctx, "alice", "wrong password")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+	_, err = uc.Login(ctx, "nobody", "password")
+	assert.ErrorIs(t, err, domain.ErrInvalidCredentials)
+}
+
+func TestUserUC_UpdateProfile(t *testing.T) {
+	ctx := context.Background()
+	uc := newTestUC()
+
+	guest, err := uc.CreateGuest(ctx)
+	require.NoError(t, err)
+
+	_, err = uc.UpdateProfile(ctx, guest.ID, "   ")
+	assert.ErrorIs(t, err, domain.ErrInvalidDisplayName)
+
+	u, err := uc.UpdateProfile(ctx, guest.ID, " Крестик ")
+	require.NoError(t, err)
+	assert.Equal(t, "Крестик", u.DisplayName)
+
+	got, err := uc.GetUser(ctx, guest.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Крестик", got.DisplayName)
+}
@@ -0,0 +1,213 @@
+// Package eventhub implements topic based publish/subscribe
+// with the bounded buffer of the last events for redelivery.
+package eventhub
+
+import "sync"
+
+type Event struct {
+	// monotonically increasing within the topic, starting from 1
+	ID   int64
+	Data interface{}
+}
+
+type Subscription struct {
+	C <-chan Event
+
+	c      chan Event
+	closed bool
+}
+
+type topic struct {
+	lastID      int64
+	buffer      []Event
+	subscribers map[*Subscription]struct{}
+	// the topic is deleted when the last subscriber leaves
+	removed bool
+}
+
+type Hub[K comparable] struct {
+	topics           map[K]*topic
+	bufferSize       int
+	subscriptionSize int
+	mu               sync.Mutex
+}
+
+// New creates the hub that keeps bufferSize last events of every topic.
+func New[K comparable](bufferSize int) *Hub[K] {
+	return &Hub[K]{
+		topics:           make(map[K]*topic),
+		bufferSize:       bufferSize,
+		subscriptionSize: bufferSize,
+	}
+}
+
+// getTopic returns the topic creating it if needed, it's for the writes only.
+func (h *Hub[K]) getTopic(key K) *topic {
+	t, ok := h.topics[key]
+	if !ok {
+		t = &topic{
+			buffer:      make([]Event, 0, h.bufferSize),
+			subscribers: make(map[*Subscription]struct{}),
+		}
+		h.topics[key] = t
+	}
+	return t
+}
+
+// Publish assigns the next id to the event and delivers it to the subscribers.
+// Subscribers that don't keep up are closed.
+func (h *Hub[K]) Publish(key K, data interface{}) Event {
+	return h.PublishFunc(key, func(int64) interface{} {
+		return data
+	})
+}
+
+// PublishFunc is like Publish, but the event data is built by the function
+// that gets the event id, so the data can contain it.
+func (h *Hub[K]) PublishFunc(key K, data func(id int64) interface{}) Event {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t := h.getTopic(key)
+
+	t.lastID++
+	event := Event{ID: t.lastID, Data: data(t.lastID)}
+
+	if h.bufferSize > 0 {
+		if len(t.buffer) == h.bufferSize {
+			copy(t.buffer, t.buffer[1:])
+			t.buffer = t.buffer[:len(t.buffer)-1]
+		}
+		t.buffer = append(t.buffer, event)
+	}
+
+	for s := range t.subscribers {
+		select {
+		case s.c <- event:
+		default:
+			h.closeSubscription(key, t, s)
+		}
+	}
+
+	return event
+}
+
+// Since returns the buffered events after lastID. The second value is false
+// if some of the events after lastID aren't in the buffer anymore
+// or lastID is ahead of the topic, e.g. the topic is removed.
+func (h *Hub[K]) Since(key K, lastID int64) ([]Event, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return h.since(h.topics[key], lastID)
+}
+
+// SinceLastID is like Since, but also returns the id of the last published event
+// of the topic, both are read at once.
+func (h *Hub[K]) SinceLastID(key K, lastID int64) ([]Event, int64, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t := h.topics[key]
+	events, complete := h.since(t, lastID)
+	if t == nil {
+		return events, 0, complete
+	}
+	return events, t.lastID, complete
+}
+
+// LastID returns the id of the last published event of the topic.
+func (h *Hub[K]) LastID(key K) int64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t, ok := h.topics[key]
+	if !ok {
+		return 0
+	}
+	return t.lastID
+}
+
+// Subscribe returns the subscription to the new events of the topic
+// and the buffered events after lastID like Since does.
+func (h *Hub[K]) Subscribe(key K, lastID int64) (*Subscription, []Event, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t := h.getTopic(key)
+
+	c := make(chan Event, h.subscriptionSize)
+	s := &Subscription{C: c, c: c}
+	t.subscribers[s] = struct{}{}
+
+	missed, complete := h.since(t, lastID)
+	return s, missed, complete
+}
+
+func (h *Hub[K]) Unsubscribe(key K, s *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t, ok := h.topics[key]
+	if !ok {
+		return
+	}
+
+	h.closeSubscription(key, t, s)
+}
+
+// Remove deletes the topic that won't get events anymore, e.g. of the finished game.
+// The topic with subscribers is deleted when the last of them leaves.
+func (h *Hub[K]) Remove(key K) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	t, ok := h.topics[key]
+	if !ok {
+		return
+	}
+
+	t.removed = true
+	if len(t.subscribers) == 0 {
+		delete(h.topics, key)
+	}
+}
+
+// since returns the events of the topic, t is nil if the topic doesn't exist.
+func (h *Hub[K]) since(t *topic, lastID int64) ([]Event, bool) {
+	var topicLastID int64
+	if t != nil {
+		topicLastID = t.lastID
+	}
+
+	if lastID > topicLastID {
+		return nil, false
+	}
+	if lastID == topicLastID {
+		return nil, true
+	}
+
+	events := make([]Event, 0, t.lastID-lastID)
+	for _, event := range t.buffer {
+		if event.ID > lastID {
+			events = append(events, event)
+		}
+	}
+
+	complete := len(events) > 0 && events[0].ID == lastID+1
+	return events, complete
+}
+
+func (h *Hub[K]) closeSubscription(key K, t *topic, s *Subscription) {
+	if s.closed {
+		return
+	}
+
+	s.closed = true
+	delete(t.subscribers, s)
+	close(s.c)
+
+	if t.removed && len(t.subscribers) == 0 {
+		delete(h.topics, key)
+	}
+}
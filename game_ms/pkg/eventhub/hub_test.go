@@ -0,0 +1,121 @@
+package eventhub
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHub_Since(t *testing.T) {
+	h := New[string](3)
+	for i := 0; i < 5; i++ {
+		h.Publish("game", i)
+	}
+	h.Publish("other", "x")
+
+	events, complete := h.Since("game", 2)
+	assert.True(t, complete)
+	assert.Equal(t, []Event{{ID: 3, Data: 2}, {ID: 4, Data: 3}, {ID: 5, Data: 4}}, events)
+
+	events, complete = h.Since("game", 1)
+	assert.False(t, complete)
+	assert.Len(t, events, 3)
+
+	events, complete = h.Since("game", 5)
+	assert.True(t, complete)
+	assert.Empty(t, events)
+
+	assert.Equal(t, int64(5), h.LastID("game"))
+	assert.Equal(t, int64(1), h.LastID("other"))
+}
+
+func TestHub_SinceLastID(t *testing.T) {
+	h := New[string](3)
+	for i := 0; i < 5; i++ {
+		h.Publish("game", i)
+	}
+
+	events, lastID, complete := h.SinceLastID("game", 3)
+	assert.True(t, complete)
+	assert.Equal(t, []Event{{ID: 4, Data: 3}, {ID: 5, Data: 4}}, events)
+	assert.Equal(t, int64(5), lastID)
+
+	_, lastID, complete = h.SinceLastID("game", 1)
+	assert.False(t, complete)
+	assert.Equal(t, int64(5), lastID)
+
+	_, lastID, complete = h.SinceLastID("unknown", 2)
+	assert.False(t, complete)
+	assert.Zero(t, lastID)
+	assert.NotContains(t, h.topics, "unknown")
+}
+
+func TestHub_Subscribe(t *testing.T) {
+	h := New[string](2)
+	h.Publish("game", "a")
+
+	sub, missed, complete := h.Subscribe("game", 0)
+	assert.True(t, complete)
+	assert.Equal(t, []Event{{ID: 1, Data: "a"}}, missed)
+
+	h.Publish("game", "b")
+	assert.Equal(t, Event{ID: 2, Data: "b"}, <-sub.C)
+
+	h.Publish("game", "c")
+	h.Publish("game", "d")
+	h.Publish("game", "e")
+
+	received := 0
+	for range sub.C {
+		received++
+	}
+	assert.Equal(t, 2, received, "slow subscription must be closed")
+
+	h.Unsubscribe("game", sub)
+}
+
+func TestHub_PublishFunc(t *testing.T) {
+	h := New[string](2)
+	h.Publish("game", "a")
+
+	event := h.PublishFunc("game", func(id int64) interface{} {
+		return id * 10
+	})
+	assert.Equal(t, Event{ID: 2, Data: int64(20)}, event)
+
+	events, complete := h.Since("game", 1)
+	assert.True(t, complete)
+	assert.Equal(t, []Event{event}, events)
+}
+
+func TestHub_ReadsDontCreateTopics(t *testing.T) {
+	h := New[string](2)
+
+	assert.Zero(t, h.LastID("game"))
+	events, complete := h.Since("game", 0)
+	assert.True(t, complete)
+	assert.Empty(t, events)
+
+	_, complete = h.Since("game", 3)
+	assert.False(t, complete, "lastID is ahead of the topic")
+
+	assert.Empty(t, h.topics)
+}
+
+func TestHub_Remove(t *testing.T) {
+	h := New[string](2)
+	h.Publish("game", "a")
+
+	h.Remove("game")
+	assert.NotContains(t, h.topics, "game", "topic without subscribers is deleted at once")
+
+	h.Publish("game", "a")
+	sub, _, _ := h.Subscribe("game", 1)
+	h.Remove("game")
+	assert.Contains(t, h.topics, "game", "subscriber still reads the topic")
+
+	h.Publish("game", "b")
+	assert.Equal(t, Event{ID: 2, Data: "b"}, <-sub.C)
+
+	h.Unsubscribe("game", sub)
+	assert.NotContains(t, h.topics, "game", "topic is deleted with the last subscriber")
+}
@@ -0,0 +1,157 @@
+// Package hmactoken issues and verifies compact tokens signed with HMAC-SHA256.
+//
+// The token is "v1.<payload>.<signature>", where payload is the JSON claims
+// and both parts are base64url encoded without padding.
+package hmactoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+const (
+	version = "v1"
+
+	MinKeySize = 32
+
+	DefaultRefreshLimit = 90 * 24 * time.Hour
+)
+
+var (
+	ErrShortKey         = errors.New("key is shorter than 32 bytes")
+	ErrEmptySubject     = errors.New("subject is empty")
+	ErrMalformedToken   = errors.New("malformed token")
+	ErrInvalidSignature = errors.New("invalid token signature")
+	ErrTokenExpired     = errors.New("token expired")
+	ErrRefreshLimit     = errors.New("token can't be refreshed anymore")
+)
+
+type Claims struct {
+	Subject   string `json:"sub"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+	// issue time of the first token, it's kept by the refreshes
+	AuthTime int64 `json:"auth_time"`
+}
+
+type Signer struct {
+	key          []byte
+	ttl          time.Duration
+	refreshLimit time.Duration
+	now          func() time.Time
+}
+
+type Opt func(s *Signer)
+
+// WithRefreshLimit replaces DefaultRefreshLimit, the time since the first token
+// of the subject after which the token can't be refreshed.
+func WithRefreshLimit(limit time.Duration) Opt {
+	return func(s *Signer) {
+		s.refreshLimit = limit
+	}
+}
+
+// New creates the signer of the tokens that live for ttl.
+func New(key []byte, ttl time.Duration, opts ...Opt) (*Signer, error) {
+	if len(key) < MinKeySize {
+		return nil, ErrShortKey
+	}
+
+	s := &Signer{key: key, ttl: ttl, refreshLimit: DefaultRefreshLimit, now: time.Now}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, nil
+}
+
+func (s *Signer) Issue(subject string) (string, Claims, error) {
+	return s.issue(subject, s.now().Unix())
+}
+
+// Refresh issues the new token of the valid one keeping its auth time.
+// Tokens are refreshed until the refresh limit after the auth time.
+func (s *Signer) Refresh(token string) (string, Claims, error) {
+	claims, err := s.Verify(token)
+	if err != nil {
+		return "", Claims{}, err
+	}
+
+	if !s.now().Before(time.Unix(claims.AuthTime, 0).Add(s.refreshLimit)) {
+		return "", Claims{}, ErrRefreshLimit
+	}
+
+	return s.issue(claims.Subject, claims.AuthTime)
+}
+
+func (s *Signer) issue(subject string, authTime int64) (string, Claims, error) {
+	if subject == "" {
+		return "", Claims{}, ErrEmptySubject
+	}
+
+	now := s.now()
+	claims := Claims{
+		Subject:   subject,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.ttl).Unix(),
+		AuthTime:  authTime,
+	}
+
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", Claims{}, err
+	}
+
+	signed := version + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signed + "." + base64.RawURLEncoding.EncodeToString(s.sign(signed)), claims, nil
+}
+
+func (s *Signer) Verify(token string) (Claims, error) {
+	signed, signature, ok := cutLast(token, ".")
+	if !ok || !strings.HasPrefix(signed, version+".") {
+		return Claims{}, ErrMalformedToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return Claims{}, ErrMalformedToken
+	}
+	if !hmac.Equal(mac, s.sign(signed)) {
+		return Claims{}, ErrInvalidSignature
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(signed, version+"."))
+	if err != nil {
+		return Claims{}, ErrMalformedToken
+	}
+
+	claims := Claims{}
+	if err := json.Unmarshal(payload, &claims); err != nil || claims.Subject == "" || claims.AuthTime == 0 {
+		return Claims{}, ErrMalformedToken
+	}
+
+	if s.now().Unix() >= claims.ExpiresAt {
+		return Claims{}, ErrTokenExpired
+	}
+
+	return claims, nil
+}
+
+func (s *Signer) sign(data string) []byte {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func cutLast(s, sep string) (string, string, bool) {
+	i := strings.LastIndex(s, sep)
+	if i < 0 {
+		return s, "", false
+	}
+	return s[:i], s[i+len(sep):], true
+}
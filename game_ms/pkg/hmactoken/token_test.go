@@ -0,0 +1,86 @@
+package hmactoken
+
+import (
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestSigner_IssueVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	s, err := New(testKey, time.Hour)
+	require.NoError(t, err)
+	s.now = func() time.Time { return now }
+
+	token, claims, err := s.Issue("alice")
+	require.NoError(t, err)
+	assert.Equal(t, Claims{Subject: "alice", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix(),
+		AuthTime: now.Unix()}, claims)
+
+	verified, err := s.Verify(token)
+	require.NoError(t, err)
+	assert.Equal(t, claims, verified)
+
+	other, err := New([]byte(strings.Repeat("x", MinKeySize)), time.Hour)
+	require.NoError(t, err)
+	_, err = other.Verify(token)
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	parts := strings.Split(token, ".")
+	forged, _, err := s.Issue("bob")
+	require.NoError(t, err)
+	_, err = s.Verify(strings.Join([]string{parts[0], strings.Split(forged, ".")[1], parts[2]}, "."))
+	assert.ErrorIs(t, err, ErrInvalidSignature)
+
+	_, err = s.Verify("garbage")
+	assert.ErrorIs(t, err, ErrMalformedToken)
+
+	withoutAuthTime := version + "." + base64.RawURLEncoding.EncodeToString(
+		[]byte(`{"sub":"alice","iat":1700000000,"exp":1700003600}`))
+	_, err = s.Verify(withoutAuthTime + "." + base64.RawURLEncoding.EncodeToString(s.sign(withoutAuthTime)))
+	assert.ErrorIs(t, err, ErrMalformedToken)
+
+	now = now.Add(time.Hour)
+	_, err = s.Verify(token)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+}
+
+func TestSigner_Refresh(t *testing.T) {
+	authTime := time.Unix(1700000000, 0)
+	now := authTime
+	s, err := New(testKey, time.Hour, WithRefreshLimit(150*time.Minute))
+	require.NoError(t, err)
+	s.now = func() time.Time { return now }
+
+	token, _, err := s.Issue("alice")
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		now = now.Add(time.Hour - time.Minute)
+
+		var claims Claims
+		token, claims, err = s.Refresh(token)
+		require.NoError(t, err)
+		assert.Equal(t, Claims{Subject: "alice", IssuedAt: now.Unix(), ExpiresAt: now.Add(time.Hour).Unix(),
+			AuthTime: authTime.Unix()}, claims)
+	}
+
+	now = now.Add(time.Hour - time.Minute)
+	_, err = s.Verify(token)
+	require.NoError(t, err, "the token is still valid")
+	_, _, err = s.Refresh(token)
+	assert.ErrorIs(t, err, ErrRefreshLimit, "but it's refreshed longer than the limit")
+
+	_, _, err = s.Refresh("garbage")
+	assert.ErrorIs(t, err, ErrMalformedToken)
+}
+
+func TestNew_ShortKey(t *testing.T) {
+	_, err := New([]byte("short"), time.Hour)
+	assert.ErrorIs(t, err, ErrShortKey)
+}
@@ -4,14 +4,19 @@ import (
 	"context"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"time"
 )
 
 type RequestLogger struct {
 	l            *slog.Logger
 	DefaultLevel slog.Level
+	// query params which values are hidden in the logged URL, e.g. credentials
+	RedactedParams []string
 }
 
+const redacted = "REDACTED"
+
 func New(l *slog.Logger, defaultLevel slog.Level) *RequestLogger {
 	return &RequestLogger{l: l, DefaultLevel: defaultLevel}
 }
@@ -20,7 +25,7 @@ func (l *RequestLogger) LogBegin(ctx context.Context, r *http.Request, requestID
 	l.l.Log(ctx, l.DefaultLevel, "request incoming",
 		slog.String("req_id", requestID),
 		slog.String("method", r.Method),
-		slog.String("url", r.URL.String()),
+		slog.String("url", l.redactURL(r.URL)),
 		slog.String("remote_addr", r.RemoteAddr),
 		slog.String("user_agent", r.UserAgent()),
 		slog.String("proto", r.Proto),
@@ -42,3 +47,22 @@ func (l *RequestLogger) LogEnd(ctx context.Context, requestID string, status int
 		slog.Duration("elapsed", elapsed),
 	)
 }
+
+func (l *RequestLogger) redactURL(u *url.URL) string {
+	query := u.Query()
+
+	found := false
+	for _, param := range l.RedactedParams {
+		if query.Has(param) {
+			query.Set(param, redacted)
+			found = true
+		}
+	}
+	if !found {
+		return u.String()
+	}
+
+	res := *u
+	res.RawQuery = query.Encode()
+	return res.String()
+}
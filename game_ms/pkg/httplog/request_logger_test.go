@@ -0,0 +1,36 @@
+package httplog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequestLogger_RedactedParams(t *testing.T) {
+	tcases := map[string]string{
+		"/ws?access_token=secret&v=1": "/ws?access_token=REDACTED&v=1",
+		"/ws?v=1":                     "/ws?v=1",
+		"/ws":                         "/ws",
+	}
+
+	for target, expected := range tcases {
+		out := &bytes.Buffer{}
+		l := New(slog.New(slog.NewJSONHandler(out, nil)), slog.LevelInfo)
+		l.RedactedParams = []string{"access_token"}
+
+		l.LogBegin(context.Background(), httptest.NewRequest(http.MethodGet, target, nil), "1")
+
+		record := struct {
+			URL string `json:"url"`
+		}{}
+		require.NoError(t, json.Unmarshal(out.Bytes(), &record), target)
+		assert.Equal(t, expected, record.URL, target)
+		assert.NotContains(t, out.String(), "secret", target)
+	}
+}
@@ -0,0 +1,122 @@
+// Package ratelimit implements token bucket rate limiting of single clients
+// and of many clients by key.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limit allows Burst events at once and Rate events per second on average.
+// Zero Rate means no limit.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l Limit) IsUnlimited() bool {
+	return l.Rate <= 0
+}
+
+// fillTime is the duration the empty bucket becomes full for.
+func (l Limit) fillTime() time.Duration {
+	return time.Duration(float64(l.Burst) / l.Rate * float64(time.Second))
+}
+
+type Bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+	mu     sync.Mutex
+}
+
+// NewBucket creates the full bucket.
+func NewBucket(limit Limit) *Bucket {
+	return newBucket(limit, time.Now)
+}
+
+func newBucket(limit Limit, now func() time.Time) *Bucket {
+	return &Bucket{limit: limit, tokens: float64(limit.Burst), last: now(), now: now}
+}
+
+// Allow takes the token if there is one.
+func (b *Bucket) Allow() bool {
+	if b.limit.IsUnlimited() {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.tokens = min(float64(b.limit.Burst), b.tokens+now.Sub(b.last).Seconds()*b.limit.Rate)
+	b.last = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// isFull reports whether the bucket is indistinguishable from the new one.
+func (b *Bucket) isFull(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return now.Sub(b.last) >= b.limit.fillTime()
+}
+
+// Limiter keeps the bucket of every key. Buckets that became full again are
+// removed from time to time, so the memory is bounded by the active keys.
+type Limiter[K comparable] struct {
+	limit     Limit
+	buckets   map[K]*Bucket
+	lastPrune time.Time
+	now       func() time.Time
+	mu        sync.Mutex
+}
+
+func New[K comparable](limit Limit) *Limiter[K] {
+	return &Limiter[K]{limit: limit, buckets: make(map[K]*Bucket), lastPrune: time.Now(), now: time.Now}
+}
+
+// Allow takes the token from the bucket of the key if there is one.
+func (l *Limiter[K]) Allow(key K) bool {
+	if l.limit.IsUnlimited() {
+		return true
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	if now.Sub(l.lastPrune) >= l.limit.fillTime() {
+		l.prune(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = newBucket(l.limit, l.now)
+		l.buckets[key] = b
+	}
+	l.mu.Unlock()
+
+	return b.Allow()
+}
+
+func (l *Limiter[K]) prune(now time.Time) {
+	for key, b := range l.buckets {
+		if b.isFull(now) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastPrune = now
+}
+
+// Len returns the count of the tracked keys.
+func (l *Limiter[K]) Len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return len(l.buckets)
+}
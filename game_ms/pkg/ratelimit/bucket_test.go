@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func (c *clock) add(d time.Duration) {
+	c.t = c.t.Add(d)
+}
+
+func TestBucket_Allow(t *testing.T) {
+	c := &clock{t: time.Unix(0, 0)}
+	b := newBucket(Limit{Rate: 2, Burst: 3}, c.now)
+
+	for i := 0; i < 3; i++ {
+		assert.True(t, b.Allow(), "burst %v", i)
+	}
+	assert.False(t, b.Allow())
+
+	c.add(500 * time.Millisecond)
+	assert.True(t, b.Allow(), "refilled one token")
+	assert.False(t, b.Allow())
+
+	c.add(time.Hour)
+	for i := 0; i < 3; i++ {
+		assert.True(t, b.Allow(), "refill is capped by burst %v", i)
+	}
+	assert.False(t, b.Allow())
+}
+
+func TestBucket_Unlimited(t *testing.T) {
+	b := NewBucket(Limit{})
+	for i := 0; i < 100; i++ {
+		assert.True(t, b.Allow())
+	}
+}
+
+func TestLimiter_Allow(t *testing.T) {
+	c := &clock{t: time.Unix(0, 0)}
+	l := New[string](Limit{Rate: 1, Burst: 2})
+	l.now = c.now
+	l.lastPrune = c.t
+
+	assert.True(t, l.Allow("a"))
+	assert.True(t, l.Allow("a"))
+	assert.False(t, l.Allow("a"))
+	assert.True(t, l.Allow("b"), "keys have own buckets")
+	assert.Equal(t, 2, l.Len())
+
+	c.add(time.Second)
+	assert.True(t, l.Allow("a"))
+	assert.Equal(t, 2, l.Len(), "buckets aren't full yet")
+
+	c.add(2 * time.Second)
+	assert.True(t, l.Allow("c"))
+	assert.Equal(t, 1, l.Len(), "full buckets are pruned")
+}